@@ -0,0 +1,102 @@
+package arkcoin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kristjank/ark-go/arkcoin/base58"
+)
+
+//AddressLength is the decoded address length - version byte and ripemd160 hash
+const AddressLength = 21
+
+var (
+	//ErrAddressChecksum is returned when address checksum does not match
+	ErrAddressChecksum = errors.New("address checksum mismatch")
+	//ErrAddressLength is returned when decoded address is not AddressLength bytes long
+	ErrAddressLength = errors.New("address has wrong length")
+)
+
+//AddressCharacterError is returned when address contains a character outside of base58 alphabet
+type AddressCharacterError struct {
+	Char     byte
+	Position int
+}
+
+//Error is to implement Error interface.
+func (e AddressCharacterError) Error() string {
+	return fmt.Sprintf("address contains invalid base58 character %q at position %d", e.Char, e.Position)
+}
+
+//AddressNetworkError is returned when address version byte belongs to another network.
+//Network holds the name of the known network the address belongs to, if any.
+type AddressNetworkError struct {
+	Version  byte
+	Expected byte
+	Network  string
+}
+
+//Error is to implement Error interface.
+func (e AddressNetworkError) Error() string {
+	network := e.Network
+	if network == "" {
+		network = "unknown network"
+	}
+	return fmt.Sprintf("address version %d belongs to %s, expected version %d", e.Version, network, e.Expected)
+}
+
+//ValidateAddress checks address encoding, checksum, length and version byte for network param.
+//Returned errors are AddressCharacterError, ErrAddressChecksum, ErrAddressLength or AddressNetworkError.
+func ValidateAddress(addr string, param *Params) error {
+	pb, err := decodeAddressBytes(addr)
+	if err != nil {
+		return err
+	}
+	if pb[0] != param.AddressHeader {
+		e := AddressNetworkError{Version: pb[0], Expected: param.AddressHeader}
+		if known := findNetworkByHeader(pb[0]); known != nil {
+			e.Network = known.Name
+		}
+		return e
+	}
+	return nil
+}
+
+//AddressNetwork returns known network params (see KnownNetworks) the valid address belongs to
+func AddressNetwork(addr string) (*Params, error) {
+	pb, err := decodeAddressBytes(addr)
+	if err != nil {
+		return nil, err
+	}
+	if known := findNetworkByHeader(pb[0]); known != nil {
+		return known, nil
+	}
+	return nil, AddressNetworkError{Version: pb[0]}
+}
+
+func decodeAddressBytes(addr string) ([]byte, error) {
+	pb, err := base58.Decode(addr)
+	switch e := err.(type) {
+	case nil:
+	case base58.CorruptInputError:
+		return nil, AddressCharacterError{Char: addr[int(e)], Position: int(e)}
+	default:
+		if err == base58.ErrChecksum {
+			return nil, ErrAddressChecksum
+		}
+		return nil, ErrAddressLength
+	}
+	if len(pb) != AddressLength {
+		return nil, ErrAddressLength
+	}
+	return pb, nil
+}
+
+func findNetworkByHeader(header byte) *Params {
+	for _, p := range KnownNetworks {
+		if p.AddressHeader == header {
+			return p
+		}
+	}
+	return nil
+}
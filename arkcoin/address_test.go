@@ -0,0 +1,59 @@
+package arkcoin
+
+import (
+	"log"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	if err := ValidateAddress("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25", ArkCoinMain); err != nil {
+		t.Error(err.Error())
+	}
+	if err := ValidateAddress("D61mfSggzbvQgTUe6JhYKH2doHaqJ3Dyib", ArkCoinDevTest); err != nil {
+		t.Error(err.Error())
+	}
+
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinDevTest)
+	if err := ValidateAddress(key.PublicKey.Address(), ArkCoinDevTest); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestValidateAddressErrors(t *testing.T) {
+	err := ValidateAddress("D61mfSggzbvQgTUe6JhYKH2doHaqJ3Dyib", ArkCoinMain)
+	netErr, ok := err.(AddressNetworkError)
+	if !ok || netErr.Network != ArkCoinDevTest.Name || netErr.Expected != ArkCoinMain.AddressHeader {
+		t.Error("expected AddressNetworkError for devnet address, got", err)
+	}
+	log.Println(t.Name(), err)
+
+	if err = ValidateAddress("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM26", ArkCoinMain); err != ErrAddressChecksum {
+		t.Error("expected ErrAddressChecksum, got", err)
+	}
+
+	err = ValidateAddress("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM0", ArkCoinMain)
+	if charErr, ok := err.(AddressCharacterError); !ok || charErr.Position != 32 {
+		t.Error("expected AddressCharacterError, got", err)
+	}
+
+	//valid base58check, but WIF length
+	wif := NewPrivateKeyFromPassword("passphrase", ArkCoinMain).WIFAddress()
+	if err = ValidateAddress(wif, ArkCoinMain); err != ErrAddressLength {
+		t.Error("expected ErrAddressLength, got", err)
+	}
+
+	if err = ValidateAddress("", ArkCoinMain); err != ErrAddressLength {
+		t.Error("expected ErrAddressLength, got", err)
+	}
+}
+
+func TestAddressNetwork(t *testing.T) {
+	param, err := AddressNetwork("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25")
+	if err != nil || param != ArkCoinMain {
+		t.Error("expected ark mainnet", err)
+	}
+	param, err = AddressNetwork("D61mfSggzbvQgTUe6JhYKH2doHaqJ3Dyib")
+	if err != nil || param != ArkCoinDevTest {
+		t.Error("expected ark devnet", err)
+	}
+}
@@ -23,6 +23,9 @@
 // As opposed to base64 and friends, base58 is typically used to
 // convert integers. You can use big.Int.SetBytes to convert arbitrary
 // bytes to an integer first, and big.Int.Bytes the other way around.
+//
+// Encode, AppendEncode and Decode work on byte arrays directly and do not
+// use big.Int. Leading zero bytes are encoded as '1' characters.
 package base58
 
 import (
@@ -87,3 +90,80 @@ func EncodeBig(dst []byte, src *big.Int) []byte {
 	}
 	return dst
 }
+
+//stackBufferLen is the size of conversion buffers kept on the stack, longer input allocates
+const stackBufferLen = 128
+
+//encodeDigits adds src bytes to base58 digits big number stored at the end of digits.
+//length is the number of digits used so far, the new number of digits is returned.
+func encodeDigits(digits []byte, length int, src []byte) int {
+	for _, b := range src {
+		carry := int(b)
+		i := 0
+		for j := len(digits) - 1; (carry != 0 || i < length) && j >= 0; j, i = j-1, i+1 {
+			carry += 256 * int(digits[j])
+			digits[j] = byte(carry % 58)
+			carry /= 58
+		}
+		length = i
+	}
+	return length
+}
+
+//appendEncodeRaw appends base58 encoding of head followed by tail to dst
+func appendEncodeRaw(dst, head, tail []byte) []byte {
+	zeros := 0
+	for zeros < len(head) && head[zeros] == 0 {
+		zeros++
+	}
+	if zeros == len(head) {
+		for zeros-len(head) < len(tail) && tail[zeros-len(head)] == 0 {
+			zeros++
+		}
+	}
+
+	//log(256)/log(58) = 1.37 digits per byte
+	size := (len(head)+len(tail))*138/100 + 1
+	var stack [stackBufferLen]byte
+	var digits []byte
+	if size <= len(stack) {
+		digits = stack[:size]
+	} else {
+		digits = make([]byte, size)
+	}
+	length := encodeDigits(digits, 0, head)
+	length = encodeDigits(digits, length, tail)
+
+	for i := 0; i < zeros; i++ {
+		dst = append(dst, alphabet[0])
+	}
+	for _, d := range digits[size-length:] {
+		dst = append(dst, alphabet[d])
+	}
+	return dst
+}
+
+//decodeRaw decodes base58 src into buf, which must be at least len(src) bytes long and zeroed.
+//Decoded bytes, including leading zeros, are returned at the end of buf.
+func decodeRaw(buf []byte, src string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	length := 0
+	for i := zeros; i < len(src); i++ {
+		carry := int(decodeMap[src[i]])
+		if carry == 0xFF {
+			return nil, CorruptInputError(i)
+		}
+		j := len(buf) - 1
+		for k := 0; (carry != 0 || k < length) && j >= 0; j, k = j-1, k+1 {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry)
+			carry >>= 8
+		}
+		length = len(buf) - 1 - j
+	}
+	return buf[len(buf)-length-zeros:], nil
+}
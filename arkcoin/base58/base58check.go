@@ -23,79 +23,68 @@ package base58
 import (
 	"bytes"
 	"crypto/sha256"
-	"encoding/hex"
 	"errors"
-	"fmt"
-	"math/big"
 )
 
-//Encode encodes byteData to base58.
-func Encode(encoded []byte) string {
-	//Perform SHA-256 twice
-	hash := sha256.Sum256(encoded)
-	hash = sha256.Sum256(hash[:])
-
-	//First 4 bytes if this double-sha'd byte array is the checksum
-	//Append this checksum to the input bytes
-	encoded = append(encoded, hash[0:4]...)
-
-	//Convert this checksum'd version to a big Int
-	bigIntEncodedChecksum := new(big.Int).SetBytes(encoded)
-
-	//Encode the big int checksum'd version into a Base58Checked string
-	base58EncodedChecksum := EncodeBig(nil, bigIntEncodedChecksum)
-
-	//Now for each zero byte we counted above we need to prepend a 1 to our
-	//base58 encoded string. The rational behind this is that base58 removes 0's (0x00).
-	//So bitcoin demands we add leading 0s back on as 1s.
-	buffer := make([]byte, 0, len(base58EncodedChecksum))
+var (
+	//ErrChecksum is returned when the embedded checksum does not match the decoded data
+	ErrChecksum = errors.New("checksum error")
+	//ErrInvalidFormat is returned when the version and/or checksum bytes are missing
+	ErrInvalidFormat = errors.New("invalid format: version and/or checksum bytes missing")
+)
 
-	//base58 alone is not enough. We need to first count each of the zero bytes
-	//which are at the beginning of the encodedCheckSum
+//Encode encodes byteData to base58 with 4 byte double sha256 checksum. byteData is not modified.
+func Encode(byteData []byte) string {
+	var stack [stackBufferLen]byte
+	return string(AppendEncode(stack[:0], byteData))
+}
 
-	for _, v := range encoded {
-		if v != 0 {
-			break
-		}
-		buffer = append(buffer, '1')
-	}
-	buffer = append(buffer, base58EncodedChecksum...)
-	return string(buffer)
+//AppendEncode appends base58check encoding of payload to dst and returns the extended buffer.
+//It does not allocate if dst has enough capacity and payload is at most 88 bytes long.
+func AppendEncode(dst, payload []byte) []byte {
+	cksum := checksum(payload)
+	return appendEncodeRaw(dst, payload, cksum[:])
 }
 
 //Decode decodes base58 value to bytes.
 func Decode(value string) ([]byte, error) {
+	return AppendDecode(nil, value)
+}
+
+//AppendDecode decodes base58check value, verifies the checksum and appends the payload to dst.
+func AppendDecode(dst []byte, value string) ([]byte, error) {
 	if len(value) < 5 {
-		return nil, errors.New("invalid input")
+		return nil, ErrInvalidFormat
 	}
-	publicKeyInt, err := DecodeToBig([]byte(value))
+
+	var stack [stackBufferLen]byte
+	var buf []byte
+	if len(value) <= len(stack) {
+		buf = stack[:len(value)]
+	} else {
+		buf = make([]byte, len(value))
+	}
+	decoded, err := decodeRaw(buf, value)
 	if err != nil {
 		return nil, err
 	}
-
-	encodedChecksum := publicKeyInt.Bytes()
-	encoded := encodedChecksum[:len(encodedChecksum)-4]
-	cksum := encodedChecksum[len(encodedChecksum)-4:]
-
-	buffer := make([]byte, 0, len(encoded))
-	for _, v := range value {
-		if v != '1' {
-			break
-		}
-		buffer = append(buffer, 0)
+	if len(decoded) < 4 {
+		return nil, ErrInvalidFormat
 	}
 
-	buffer = append(buffer, encoded...)
-
-	//Perform SHA-256 twice
-	hash := sha256.Sum256(buffer)
-	hash = sha256.Sum256(hash[:])
-
-	if !bytes.Equal(hash[:4], cksum) {
-		return nil,
-			fmt.Errorf("%s checksum did not match to the embeded one:%s, should be :%s",
-				value, hex.EncodeToString(hash[:4]), hex.EncodeToString(cksum))
+	payload := decoded[:len(decoded)-4]
+	cksum := checksum(payload)
+	if !bytes.Equal(cksum[:], decoded[len(decoded)-4:]) {
+		return nil, ErrChecksum
 	}
+	return append(dst, payload...), nil
+}
 
-	return buffer, err
+//checksum returns first 4 bytes of double sha256 hash
+func checksum(payload []byte) [4]byte {
+	hash := sha256.Sum256(payload)
+	hash = sha256.Sum256(hash[:])
+	var cksum [4]byte
+	copy(cksum[:], hash[:4])
+	return cksum
 }
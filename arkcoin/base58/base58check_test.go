@@ -4,7 +4,13 @@
 
 package base58
 
-import "testing"
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"math/rand"
+	"testing"
+)
 
 var checkEncodingStringTests = []struct {
 	in  string
@@ -57,3 +63,143 @@ func TestBase58Check(t *testing.T) {
 	}
 
 }
+
+//encodeBigCheck is the previous math/big based Encode, kept for comparison and benchmarks
+func encodeBigCheck(payload []byte) string {
+	hash := sha256.Sum256(payload)
+	hash = sha256.Sum256(hash[:])
+	encoded := append(append([]byte{}, payload...), hash[0:4]...)
+
+	buffer := make([]byte, 0, len(encoded))
+	for _, v := range encoded {
+		if v != 0 {
+			break
+		}
+		buffer = append(buffer, '1')
+	}
+	return string(EncodeBig(buffer, new(big.Int).SetBytes(encoded)))
+}
+
+//decodeBigCheck is the previous math/big based Decode, for benchmarks
+func decodeBigCheck(value string) ([]byte, error) {
+	n, err := DecodeToBig([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	encodedChecksum := n.Bytes()
+	buffer := make([]byte, 0, len(encodedChecksum))
+	for _, v := range value {
+		if v != '1' {
+			break
+		}
+		buffer = append(buffer, 0)
+	}
+	buffer = append(buffer, encodedChecksum[:len(encodedChecksum)-4]...)
+	hash := sha256.Sum256(buffer)
+	hash = sha256.Sum256(hash[:])
+	if !bytes.Equal(hash[:4], encodedChecksum[len(encodedChecksum)-4:]) {
+		return nil, ErrChecksum
+	}
+	return buffer, nil
+}
+
+func TestEncodeSameAsBig(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		payload := make([]byte, rnd.Intn(150))
+		rnd.Read(payload)
+		//some payloads with leading zero bytes
+		for j := 0; j < len(payload) && j < i%5; j++ {
+			payload[j] = 0
+		}
+
+		encoded := Encode(payload)
+		if expected := encodeBigCheck(payload); encoded != expected {
+			t.Fatalf("%x encoded to %s, expected %s", payload, encoded, expected)
+		}
+		decoded, err := Decode(encoded)
+		if err != nil || !bytes.Equal(decoded, payload) {
+			t.Fatalf("%x decoded to %x, %v", payload, decoded, err)
+		}
+	}
+}
+
+func TestLeadingZeros(t *testing.T) {
+	for _, payload := range [][]byte{{0, 0, 0, 0x14, 0x01}, {0, 0, 0, 0, 0, 0}, {0}, {}} {
+		decoded, err := Decode(Encode(payload))
+		if err != nil || !bytes.Equal(decoded, payload) {
+			t.Errorf("%x decoded to %x, %v", payload, decoded, err)
+		}
+	}
+
+	//payload of zero bytes with zero byte first in checksum
+	for i := 0; i < 1024; i++ {
+		payload := []byte{0, 0, byte(i >> 8), byte(i)}
+		if cksum := checksum(payload); cksum[0] != 0 {
+			continue
+		}
+		decoded, err := Decode(Encode(payload))
+		if err != nil || !bytes.Equal(decoded, payload) {
+			t.Errorf("%x decoded to %x, %v", payload, decoded, err)
+		}
+	}
+}
+
+func TestEncodeDoesNotModifyInput(t *testing.T) {
+	payload := make([]byte, 3, 16)
+	payload[0] = 0x14
+	Encode(payload)
+	if extended := payload[:7]; !bytes.Equal(extended[3:], []byte{0, 0, 0, 0}) {
+		t.Error("Encode wrote checksum into payload capacity", extended)
+	}
+}
+
+func TestAppendEncodeAllocs(t *testing.T) {
+	payload := []byte("\x17abcdefghijklmnopqrst")
+	dst := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = AppendEncode(dst[:0], payload)
+	})
+	if allocs != 0 {
+		t.Error("AppendEncode allocates", allocs)
+	}
+	if string(dst) != Encode(payload) {
+		t.Error("AppendEncode differs from Encode", string(dst))
+	}
+}
+
+var benchAddress = "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25"
+
+func BenchmarkEncode(b *testing.B) {
+	payload, _ := Decode(benchAddress)
+	for i := 0; i < b.N; i++ {
+		Encode(payload)
+	}
+}
+
+func BenchmarkAppendEncode(b *testing.B) {
+	payload, _ := Decode(benchAddress)
+	dst := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		dst = AppendEncode(dst[:0], payload)
+	}
+}
+
+func BenchmarkEncodeBig(b *testing.B) {
+	payload, _ := Decode(benchAddress)
+	for i := 0; i < b.N; i++ {
+		encodeBigCheck(payload)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Decode(benchAddress)
+	}
+}
+
+func BenchmarkDecodeBig(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		decodeBigCheck(benchAddress)
+	}
+}
@@ -0,0 +1,277 @@
+package arkcoin
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/kristjank/ark-go/arkcoin/base58"
+	"golang.org/x/crypto/scrypt"
+)
+
+//bip38 non-EC multiplied key prefix and flags
+const (
+	bip38Length         = 39
+	bip38FlagCompressed = 0xe0
+	bip38FlagNone       = 0xc0
+)
+
+var bip38Prefix = []byte{0x01, 0x42}
+
+//KeystoreVersion is the version of the JSON keystore format written by NewKeystore
+const KeystoreVersion = 1
+
+var (
+	//ErrBIP38Format is returned when encrypted key is not a non-EC multiplied BIP38 key
+	ErrBIP38Format = errors.New("encrypted key is not a valid BIP38 key")
+	//ErrBIP38Password is returned when decrypted key does not match address hash - the password is wrong
+	ErrBIP38Password = errors.New("wrong password for encrypted key")
+	//ErrKeystoreVersion is returned for unsupported keystore version or cipher
+	ErrKeystoreVersion = errors.New("unsupported keystore version or cipher")
+	//ErrKeystoreNetwork is returned when keystore network is not one of KnownNetworks
+	ErrKeystoreNetwork = errors.New("unknown keystore network")
+	//ErrKeystoreAddress is returned when decrypted key does not match keystore address
+	ErrKeystoreAddress = errors.New("decrypted key does not match keystore address")
+	//ErrScryptParams is returned for scrypt parameters which are invalid or exceed MaxScryptParams
+	ErrScryptParams = errors.New("scrypt parameters are invalid or too expensive")
+)
+
+//ScryptParams are scrypt key derivation parameters used for key encryption
+type ScryptParams struct {
+	N int
+	R int
+	P int
+}
+
+//DefaultScryptParams are the BIP38 standard parameters (N=16384, r=8, p=8)
+var DefaultScryptParams = ScryptParams{N: 16384, R: 8, P: 8}
+
+//MaxScryptParams limit parameters of keystores, so a corrupted or tampered keystore can not exhaust
+//memory or CPU when decrypted
+var MaxScryptParams = ScryptParams{N: 4 * 16384, R: 2 * 8, P: 4 * 8}
+
+//validate checks that N is a power of two and all parameters are between 1 and MaxScryptParams
+func (sp ScryptParams) validate() error {
+	if sp.N < 2 || sp.N&(sp.N-1) != 0 || sp.N > MaxScryptParams.N || sp.R < 1 || sp.R > MaxScryptParams.R || sp.P < 1 || sp.P > MaxScryptParams.P {
+		return ErrScryptParams
+	}
+	return nil
+}
+
+//BIP38Encrypt encrypts private key with password and returns BIP38 (6P...) string.
+//Address hash is computed from the key address on its network params.
+func (priv *PrivateKey) BIP38Encrypt(password string) (string, error) {
+	return bip38Encrypt(priv, password, DefaultScryptParams)
+}
+
+//BIP38Decrypt decrypts BIP38 encrypted key with password for network param.
+//ErrBIP38Password is returned if password is wrong.
+func BIP38Decrypt(encrypted, password string, param *Params) (*PrivateKey, error) {
+	return bip38Decrypt(encrypted, password, param, DefaultScryptParams)
+}
+
+func bip38Encrypt(priv *PrivateKey, password string, sp ScryptParams) (string, error) {
+	addrHash := bip38AddressHash(priv.PublicKey.Address())
+	derived, err := scrypt.Key([]byte(password), addrHash, sp.N, sp.R, sp.P, 64)
+	if err != nil {
+		return "", err
+	}
+
+	flag := byte(bip38FlagNone)
+	if priv.PublicKey.isCompressed {
+		flag = bip38FlagCompressed
+	}
+
+	buf := make([]byte, 0, bip38Length)
+	buf = append(buf, bip38Prefix...)
+	buf = append(buf, flag)
+	buf = append(buf, addrHash...)
+	encrypted, err := bip38Cipher(paddedAppend(32, nil, priv.D.Bytes()), derived, true)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(append(buf, encrypted...)), nil
+}
+
+func bip38Decrypt(encrypted, password string, param *Params, sp ScryptParams) (*PrivateKey, error) {
+	pb, err := base58.Decode(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if len(pb) != bip38Length || !bytes.Equal(pb[:2], bip38Prefix) {
+		return nil, ErrBIP38Format
+	}
+	flag := pb[2]
+	if flag != bip38FlagCompressed && flag != bip38FlagNone {
+		return nil, ErrBIP38Format
+	}
+	addrHash := pb[3:7]
+
+	derived, err := scrypt.Key([]byte(password), addrHash, sp.N, sp.R, sp.P, 64)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := bip38Cipher(pb[7:], derived, false)
+	if err != nil {
+		return nil, err
+	}
+
+	priv := NewPrivateKey(keyBytes, param)
+	priv.PublicKey.isCompressed = flag == bip38FlagCompressed
+	if !bytes.Equal(bip38AddressHash(priv.PublicKey.Address()), addrHash) {
+		return nil, ErrBIP38Password
+	}
+	return priv, nil
+}
+
+//bip38Cipher encrypts or decrypts 32 bytes in two AES-256 blocks, xored with first half of derived key
+func bip38Cipher(data, derived []byte, encrypt bool) ([]byte, error) {
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 32)
+	for i := 0; i < 32; i += aes.BlockSize {
+		if encrypt {
+			var xored [aes.BlockSize]byte
+			for j := range xored {
+				xored[j] = data[i+j] ^ derived[i+j]
+			}
+			block.Encrypt(out[i:], xored[:])
+		} else {
+			block.Decrypt(out[i:], data[i:i+aes.BlockSize])
+			for j := 0; j < aes.BlockSize; j++ {
+				out[i+j] ^= derived[i+j]
+			}
+		}
+	}
+	return out, nil
+}
+
+func bip38AddressHash(address string) []byte {
+	h := sha256.Sum256([]byte(address))
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
+
+//Keystore is JSON keystore file with BIP38 encrypted private key and its KDF parameters
+type Keystore struct {
+	Version int            `json:"version"`
+	Address string         `json:"address"`
+	Network string         `json:"network"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+}
+
+//KeystoreCrypto holds encrypted key and parameters needed to decrypt it
+type KeystoreCrypto struct {
+	Cipher    string          `json:"cipher"`
+	Encrypted string          `json:"encrypted"`
+	KDF       string          `json:"kdf"`
+	KDFParams KeystoreKDFInfo `json:"kdfparams"`
+}
+
+//KeystoreKDFInfo are scrypt parameters, salt is the hex encoded BIP38 address hash
+type KeystoreKDFInfo struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+//NewKeystore encrypts private key with password using scrypt params sp (see DefaultScryptParams).
+//Key network params must be one of KnownNetworks, so the keystore can be decrypted on import.
+func NewKeystore(priv *PrivateKey, password string, sp ScryptParams) (*Keystore, error) {
+	if findNetworkByName(priv.PublicKey.param.Name) == nil {
+		return nil, ErrKeystoreNetwork
+	}
+	if err := sp.validate(); err != nil {
+		return nil, err
+	}
+	encrypted, err := bip38Encrypt(priv, password, sp)
+	if err != nil {
+		return nil, err
+	}
+	address := priv.PublicKey.Address()
+	return &Keystore{
+		Version: KeystoreVersion,
+		Address: address,
+		Network: priv.PublicKey.param.Name,
+		Crypto: KeystoreCrypto{
+			Cipher:    "bip38",
+			Encrypted: encrypted,
+			KDF:       "scrypt",
+			KDFParams: KeystoreKDFInfo{
+				N:     sp.N,
+				R:     sp.R,
+				P:     sp.P,
+				DKLen: 64,
+				Salt:  hex.EncodeToString(bip38AddressHash(address)),
+			},
+		},
+	}, nil
+}
+
+//Decrypt returns the private key from keystore, ErrBIP38Password is returned if password is wrong.
+//Scrypt parameters above MaxScryptParams are rejected with ErrScryptParams.
+func (ks *Keystore) Decrypt(password string) (*PrivateKey, error) {
+	if ks.Version != KeystoreVersion || ks.Crypto.Cipher != "bip38" || ks.Crypto.KDF != "scrypt" || ks.Crypto.KDFParams.DKLen != 64 {
+		return nil, ErrKeystoreVersion
+	}
+	param := findNetworkByName(ks.Network)
+	if param == nil {
+		return nil, ErrKeystoreNetwork
+	}
+	kdf := ks.Crypto.KDFParams
+	sp := ScryptParams{N: kdf.N, R: kdf.R, P: kdf.P}
+	if err := sp.validate(); err != nil {
+		return nil, err
+	}
+	priv, err := bip38Decrypt(ks.Crypto.Encrypted, password, param, sp)
+	if err != nil {
+		return nil, err
+	}
+	if priv.PublicKey.Address() != ks.Address {
+		return nil, ErrKeystoreAddress
+	}
+	return priv, nil
+}
+
+//WriteKeystore encrypts private key with password and writes keystore JSON to filename, readable by owner only
+func WriteKeystore(filename string, priv *PrivateKey, password string) error {
+	ks, err := NewKeystore(priv, password, DefaultScryptParams)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0600)
+}
+
+//ReadKeystore reads keystore JSON from filename, use Decrypt to get the private key
+func ReadKeystore(filename string) (*Keystore, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var ks Keystore
+	if err = json.Unmarshal(b, &ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
+
+func findNetworkByName(name string) *Params {
+	for _, p := range KnownNetworks {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package arkcoin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kristjank/ark-go/arkcoin/base58"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestBIP38(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	encrypted, err := key.BIP38Encrypt("TestingOneTwoThree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), encrypted)
+	if !strings.HasPrefix(encrypted, "6P") {
+		t.Error("wrong BIP38 prefix", encrypted)
+	}
+
+	decrypted, err := BIP38Decrypt(encrypted, "TestingOneTwoThree", ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.WIFAddress() != key.WIFAddress() {
+		t.Error("decrypted key does not match")
+	}
+
+	if _, err = BIP38Decrypt(encrypted, "TestingOneTwoThre", ArkCoinMain); err != ErrBIP38Password {
+		t.Error("expected ErrBIP38Password, got", err)
+	}
+	if _, err = BIP38Decrypt(key.WIFAddress(), "TestingOneTwoThree", ArkCoinMain); err != ErrBIP38Format {
+		t.Error("expected ErrBIP38Format, got", err)
+	}
+}
+
+//BIP38 test vector, no compression. Address hash is not checked, as Ark addresses skip the sha256 step.
+func TestBIP38Vector(t *testing.T) {
+	pb, _ := base58.Decode("6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg")
+	derived, err := scrypt.Key([]byte("TestingOneTwoThree"), pb[3:7], 16384, 8, 8, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBytes, _ := bip38Cipher(pb[7:], derived, false)
+	key := NewPrivateKey(keyBytes, BitcoinMain)
+	key.PublicKey.isCompressed = false
+	if key.WIFAddress() != "5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR" {
+		t.Error("wrong decrypted key", key.WIFAddress())
+	}
+
+	encrypted, _ := bip38Cipher(keyBytes, derived, true)
+	if !bytes.Equal(encrypted, pb[7:]) {
+		t.Error("wrong encrypted key")
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinDevTest)
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "delegate.json")
+	if err = WriteKeystore(filename, key, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	ks, err := ReadKeystore(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks.Address != key.PublicKey.Address() || ks.Network != ArkCoinDevTest.Name {
+		t.Error("wrong keystore address or network", ks.Address, ks.Network)
+	}
+
+	decrypted, err := ks.Decrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.PublicKey.Address() != key.PublicKey.Address() {
+		t.Error("decrypted key does not match")
+	}
+	if _, err = ks.Decrypt("wrong"); err != ErrBIP38Password {
+		t.Error("expected ErrBIP38Password, got", err)
+	}
+}
+
+func TestKeystoreScryptParams(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	ks, err := NewKeystore(key, "secret", ScryptParams{N: 1024, R: 8, P: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks.Crypto.KDFParams.N != 1024 || ks.Crypto.KDFParams.P != 1 {
+		t.Error("kdf params not stored", ks.Crypto.KDFParams)
+	}
+	if _, err = ks.Decrypt("secret"); err != nil {
+		t.Error(err.Error())
+	}
+
+	for _, kdf := range []KeystoreKDFInfo{{N: 1 << 30, R: 8, P: 1}, {N: 1000, R: 8, P: 1}, {N: 1024, R: 0, P: 1}, {N: 1024, R: 8, P: 1 << 20}} {
+		tampered := *ks
+		kdf.DKLen, kdf.Salt = 64, ks.Crypto.KDFParams.Salt
+		tampered.Crypto.KDFParams = kdf
+		if _, err = tampered.Decrypt("secret"); err != ErrScryptParams {
+			t.Error("expected ErrScryptParams, got", kdf, err)
+		}
+	}
+	if _, err = NewKeystore(key, "secret", ScryptParams{N: 1 << 20, R: 8, P: 8}); err != ErrScryptParams {
+		t.Error("expected ErrScryptParams, got", err)
+	}
+
+	ks.Network = "unknown"
+	if _, err = ks.Decrypt("secret"); err != ErrKeystoreNetwork {
+		t.Error("expected ErrKeystoreNetwork, got", err)
+	}
+}
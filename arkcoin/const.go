@@ -6,37 +6,48 @@ var (
 
 	//Settings below are obsolete
 	ArkCoinMain = &Params{
+		Name:                   "ark-mainnet",
 		DumpedPrivateKeyHeader: []byte{170}, //wif
 		AddressHeader:          23,          //0x17
 		//P2SHHeader:             5,
-		//HDPrivateKeyID:         []byte{0x04, 0x88, 0xad, 0xe4},
-		//HDPublicKeyID:          []byte{0x04, 0x88, 0xb2, 0x1e},
+		HDPrivateKeyID: []byte{0x02, 0xbf, 0x45, 0x30},
+		HDPublicKeyID:  []byte{0x02, 0xbf, 0x49, 0x68},
+		HDCoinType:     111,
 	}
 	ArkCoinDevTest = &Params{
+		Name:                   "ark-devnet",
 		DumpedPrivateKeyHeader: []byte{239}, //wif
 		AddressHeader:          30,          //0x17
 		//P2SHHeader:             5,
-		//HDPrivateKeyID:         []byte{0x04, 0x88, 0xad, 0xe4},
-		//HDPublicKeyID:          []byte{0x04, 0x88, 0xb2, 0x1e},
+		HDPrivateKeyID: []byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:  []byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:     1,
 	}
 
 	BitcoinMain = &Params{
+		Name:                   "bitcoin-mainnet",
 		DumpedPrivateKeyHeader: []byte{128},
 		AddressHeader:          0,
 		//P2SHHeader:             5,
-		//HDPrivateKeyID:         []byte{0x04, 0x88, 0xad, 0xe4},
-		//HDPublicKeyID:          []byte{0x04, 0x88, 0xb2, 0x1e},
+		HDPrivateKeyID: []byte{0x04, 0x88, 0xad, 0xe4},
+		HDPublicKeyID:  []byte{0x04, 0x88, 0xb2, 0x1e},
+		HDCoinType:     0,
 	}
 	//BitcoinTest is params for test net.
 	BitcoinTest = &Params{
+		Name:                   "bitcoin-testnet",
 		DumpedPrivateKeyHeader: []byte{239},
 		AddressHeader:          111,
 		P2SHHeader:             196,
-		//HDPrivateKeyID:         []byte{0x04, 0x35, 0x83, 0x94},
-		//HDPublicKeyID:          []byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID:         []byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:          []byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:             1,
 	}
 )
 
+//KnownNetworks lists params used to tell which network an address belongs to
+var KnownNetworks = []*Params{ArkCoinMain, ArkCoinDevTest, BitcoinMain, BitcoinTest}
+
 //SetActiveCoinConfiguration should be called right after Network config is received
 //it sets the coin parametes
 func SetActiveCoinConfiguration(params *Params) {
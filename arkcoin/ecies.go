@@ -0,0 +1,157 @@
+package arkcoin
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+//MaxVendorFieldLength is the maximum length of transaction vendor field in bytes
+const MaxVendorFieldLength = 64
+
+//MemoPrefix marks vendor field holding encrypted memo
+const MemoPrefix = "~"
+
+const (
+	eciesNonceLength = 12
+	eciesTagLength   = 16
+	memoSaltLength   = 8
+	//memoOverhead is the number of encoded bytes added to memo text - salt and GCM tag
+	memoOverhead = memoSaltLength + eciesTagLength
+
+	//MaxMemoLength is the longest memo text in bytes that still fits the vendor field when encrypted
+	MaxMemoLength = (MaxVendorFieldLength-len(MemoPrefix))*6/8 - memoOverhead
+)
+
+var memoEncoding = base64.RawURLEncoding
+
+var (
+	//ErrECIESDecrypt is returned when ciphertext was not encrypted to this key or was modified
+	ErrECIESDecrypt = errors.New("unable to decrypt, wrong key or corrupted ciphertext")
+	//ErrMemoTooLong is returned when encrypted memo does not fit the vendor field (see MaxMemoLength)
+	ErrMemoTooLong = errors.New("memo is too long to fit the vendor field when encrypted")
+	//ErrMemoFormat is returned when vendor field is not an encrypted memo
+	ErrMemoFormat = errors.New("vendor field is not an encrypted memo")
+)
+
+//eciesKDF derives length bytes from shared secret and info (ANSI X9.63 KDF with SHA-256)
+func eciesKDF(secret, info []byte, length int) []byte {
+	out := make([]byte, 0, length+sha256.Size)
+	var counter [4]byte
+	for i := uint32(1); len(out) < length; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write(counter[:])
+		h.Write(secret)
+		h.Write(info)
+		out = h.Sum(out)
+	}
+	return out[:length]
+}
+
+//eciesGCM returns AES-256-GCM cipher and nonce for shared secret of priv and pub
+func eciesGCM(priv *btcec.PrivateKey, pub *btcec.PublicKey, info []byte) (cipher.AEAD, []byte, error) {
+	secret := btcec.GenerateSharedSecret(priv, pub)
+	keyNonce := eciesKDF(secret, info, 32+eciesNonceLength)
+	block, err := aes.NewCipher(keyNonce[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, keyNonce[32:], nil
+}
+
+//EncryptECIES encrypts plaintext to public key. Output is ephemeral compressed public key (33 bytes),
+//followed by AES-256-GCM ciphertext and tag. Key and nonce are derived from ECDH shared secret.
+func (pub *PublicKey) EncryptECIES(plaintext []byte) ([]byte, error) {
+	ephemeral, err := btcec.NewPrivateKey(secp256k1)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralPub := ephemeral.PubKey().SerializeCompressed()
+
+	gcm, nonce, err := eciesGCM(ephemeral, pub.PublicKey, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(ephemeralPub, nonce, plaintext, nil), nil
+}
+
+//DecryptECIES decrypts ciphertext created with EncryptECIES for public key of priv
+func (priv *PrivateKey) DecryptECIES(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < btcec.PubKeyBytesLenCompressed+eciesTagLength {
+		return nil, ErrECIESDecrypt
+	}
+	ephemeralPub := ciphertext[:btcec.PubKeyBytesLenCompressed]
+	ephemeral, err := btcec.ParsePubKey(ephemeralPub, secp256k1)
+	if err != nil {
+		return nil, ErrECIESDecrypt
+	}
+
+	gcm, nonce, err := eciesGCM(priv.PrivateKey, ephemeral, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[btcec.PubKeyBytesLenCompressed:], nil)
+	if err != nil {
+		return nil, ErrECIESDecrypt
+	}
+	return plaintext, nil
+}
+
+//EncryptMemo encrypts memo from sender to recipient for the transaction vendor field.
+//Instead of an ephemeral key the sender key is used, because its public key is already part of
+//the transaction, so only a random salt and the tag are added to the memo (see MaxMemoLength).
+//Both the recipient and the sender can decrypt the memo with DecryptMemo.
+func EncryptMemo(sender *PrivateKey, recipient *PublicKey, memo string) (string, error) {
+	if len(memo) > MaxMemoLength {
+		return "", ErrMemoTooLong
+	}
+	salt := make([]byte, memoSaltLength, memoSaltLength+len(memo)+eciesTagLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	gcm, nonce, err := eciesGCM(sender.PrivateKey, recipient.PublicKey, salt)
+	if err != nil {
+		return "", err
+	}
+	return MemoPrefix + memoEncoding.EncodeToString(gcm.Seal(salt, nonce, []byte(memo), nil)), nil
+}
+
+//DecryptMemo decrypts vendor field created with EncryptMemo. key is the recipient private key
+//and counterparty is the transaction sender public key (or the other way around, for sender).
+func DecryptMemo(key *PrivateKey, counterparty *PublicKey, vendorField string) (string, error) {
+	if !IsEncryptedMemo(vendorField) {
+		return "", ErrMemoFormat
+	}
+	raw, err := memoEncoding.DecodeString(strings.TrimPrefix(vendorField, MemoPrefix))
+	if err != nil || len(raw) < memoOverhead {
+		return "", ErrMemoFormat
+	}
+
+	gcm, nonce, err := eciesGCM(key.PrivateKey, counterparty.PublicKey, raw[:memoSaltLength])
+	if err != nil {
+		return "", err
+	}
+	memo, err := gcm.Open(nil, nonce, raw[memoSaltLength:], nil)
+	if err != nil {
+		return "", ErrECIESDecrypt
+	}
+	return string(memo), nil
+}
+
+//IsEncryptedMemo reports whether vendor field looks like memo created with EncryptMemo
+func IsEncryptedMemo(vendorField string) bool {
+	return strings.HasPrefix(vendorField, MemoPrefix) && len(vendorField) <= MaxVendorFieldLength
+}
@@ -0,0 +1,73 @@
+package arkcoin
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestECIES(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	plaintext := []byte("payout reference for november")
+
+	ciphertext, err := key.PublicKey.EncryptECIES(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := key.DecryptECIES(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Error("decrypted text differs", string(decrypted))
+	}
+
+	other := NewPrivateKeyFromPassword("this is another passphrase", ArkCoinMain)
+	if _, err = other.DecryptECIES(ciphertext); err != ErrECIESDecrypt {
+		t.Error("decrypted with wrong key", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err = key.DecryptECIES(ciphertext); err != ErrECIESDecrypt {
+		t.Error("decrypted modified ciphertext", err)
+	}
+}
+
+func TestEncryptMemo(t *testing.T) {
+	delegate := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	voter := NewPrivateKeyFromPassword("this is another passphrase", ArkCoinMain)
+	memo := strings.Repeat("m", MaxMemoLength)
+
+	field, err := EncryptMemo(delegate, voter.PublicKey, memo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), field, len(field))
+	if len(field) > MaxVendorFieldLength || !IsEncryptedMemo(field) {
+		t.Error("encrypted memo does not fit vendor field", len(field))
+	}
+
+	for _, k := range []struct {
+		key          *PrivateKey
+		counterparty *PublicKey
+	}{{voter, delegate.PublicKey}, {delegate, voter.PublicKey}} {
+		decrypted, err := DecryptMemo(k.key, k.counterparty, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != memo {
+			t.Error("decrypted memo differs", decrypted)
+		}
+	}
+
+	third := NewPrivateKeyFromPassword("third passphrase", ArkCoinMain)
+	if _, err = DecryptMemo(third, delegate.PublicKey, field); err != ErrECIESDecrypt {
+		t.Error("decrypted memo with wrong key", err)
+	}
+	if _, err = DecryptMemo(voter, delegate.PublicKey, "plain vendor field"); err != ErrMemoFormat {
+		t.Error("plain vendor field accepted", err)
+	}
+	if _, err = EncryptMemo(delegate, voter.PublicKey, memo+"m"); err != ErrMemoTooLong {
+		t.Error("too long memo accepted", err)
+	}
+}
@@ -0,0 +1,310 @@
+package arkcoin
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/kristjank/ark-go/arkcoin/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+//HardenedKeyStart is the index of the first hardened child key (BIP32)
+const HardenedKeyStart uint32 = 0x80000000
+
+//serializedExtendedKeyLen is version(4) depth(1) fingerprint(4) child(4) chaincode(32) key(33)
+const serializedExtendedKeyLen = 78
+
+var (
+	//ErrInvalidSeedLength is returned when seed is shorter than 128 or longer than 512 bits
+	ErrInvalidSeedLength = errors.New("seed length must be between 128 and 512 bits")
+	//ErrDeriveHardenedFromPublic is returned when hardened child is requested from a public extended key
+	ErrDeriveHardenedFromPublic = errors.New("cannot derive a hardened key from a public key")
+	//ErrNotPrivExtKey is returned when private key is requested from a public extended key
+	ErrNotPrivExtKey = errors.New("extended key is not a private key")
+	//ErrInvalidChild is returned for the (very unlikely) invalid child index, next index should be used
+	ErrInvalidChild = errors.New("the extended key at this index is invalid")
+	//ErrInvalidExtendedKey is returned when serialized extended key can not be parsed
+	ErrInvalidExtendedKey = errors.New("the provided serialized extended key is not valid")
+	//ErrUnknownHDKeyID is returned when version bytes do not match the network params
+	ErrUnknownHDKeyID = errors.New("extended key version does not match network params")
+	//ErrInvalidDerivationPath is returned when derivation path can not be parsed
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+)
+
+var masterKey = []byte("Bitcoin seed")
+
+//ExtendedKey is a BIP32 extended private or public key
+type ExtendedKey struct {
+	key       []byte //32 bytes for private, 33 bytes compressed for public key
+	chainCode []byte
+	parentFP  []byte
+	depth     byte
+	childNum  uint32
+	isPrivate bool
+	param     *Params
+}
+
+//NewMasterKey creates master extended private key from seed (see NewSeed for BIP39 seeds)
+func NewMasterKey(seed []byte, param *Params) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeedLength
+	}
+	mac := hmac.New(sha512.New, masterKey)
+	mac.Write(seed)
+	lr := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(lr[:32])
+	if k.Sign() == 0 || k.Cmp(secp256k1.N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	return &ExtendedKey{
+		key:       lr[:32],
+		chainCode: lr[32:],
+		parentFP:  []byte{0, 0, 0, 0},
+		isPrivate: true,
+		param:     param,
+	}, nil
+}
+
+//IsPrivate returns true for extended private keys
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.isPrivate
+}
+
+//Depth returns number of derivations from master key
+func (k *ExtendedKey) Depth() byte {
+	return k.depth
+}
+
+//ChildIndex returns the index of this key at its parent (hardened indexes are >= HardenedKeyStart)
+func (k *ExtendedKey) ChildIndex() uint32 {
+	return k.childNum
+}
+
+func (k *ExtendedKey) pubKeyBytes() []byte {
+	if !k.isPrivate {
+		return k.key
+	}
+	x, y := secp256k1.ScalarBaseMult(k.key)
+	return (&btcec.PublicKey{Curve: secp256k1, X: x, Y: y}).SerializeCompressed()
+}
+
+//Child derives child extended key at index i. Indexes >= HardenedKeyStart are hardened.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	isHardened := i >= HardenedKeyStart
+	if isHardened && !k.isPrivate {
+		return nil, ErrDeriveHardenedFromPublic
+	}
+
+	var data []byte
+	if isHardened {
+		data = append([]byte{0x0}, paddedAppend(32, nil, k.key)...)
+	} else {
+		data = k.pubKeyBytes()
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], i)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	lr := mac.Sum(nil)
+	il := new(big.Int).SetBytes(lr[:32])
+	if il.Cmp(secp256k1.N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	var childKey []byte
+	if k.isPrivate {
+		ki := new(big.Int).SetBytes(k.key)
+		ki.Add(ki, il)
+		ki.Mod(ki, secp256k1.N)
+		if ki.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		childKey = paddedAppend(32, nil, ki.Bytes())
+	} else {
+		ilx, ily := secp256k1.ScalarBaseMult(lr[:32])
+		pub, err := btcec.ParsePubKey(k.key, secp256k1)
+		if err != nil {
+			return nil, err
+		}
+		x, y := secp256k1.Add(ilx, ily, pub.X, pub.Y)
+		if x.Sign() == 0 || y.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		childKey = (&btcec.PublicKey{Curve: secp256k1, X: x, Y: y}).SerializeCompressed()
+	}
+
+	return &ExtendedKey{
+		key:       childKey,
+		chainCode: lr[32:],
+		parentFP:  hash160(k.pubKeyBytes())[:4],
+		depth:     k.depth + 1,
+		childNum:  i,
+		isPrivate: k.isPrivate,
+		param:     k.param,
+	}, nil
+}
+
+//Derive derives the key at path relative to k, for example "m/44'/111'/0'/0/0"
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, i := range indexes {
+		if key, err = key.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+//Neuter returns the extended public key of k. Public key is returned unchanged.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.isPrivate {
+		return k
+	}
+	return &ExtendedKey{
+		key:       k.pubKeyBytes(),
+		chainCode: k.chainCode,
+		parentFP:  k.parentFP,
+		depth:     k.depth,
+		childNum:  k.childNum,
+		param:     k.param,
+	}
+}
+
+//PrivateKey returns the PrivateKey, to use with Sign and WIFAddress
+func (k *ExtendedKey) PrivateKey() (*PrivateKey, error) {
+	if !k.isPrivate {
+		return nil, ErrNotPrivExtKey
+	}
+	return NewPrivateKey(k.key, k.param), nil
+}
+
+//PublicKey returns the compressed PublicKey, to use with Address and Verify
+func (k *ExtendedKey) PublicKey() (*PublicKey, error) {
+	return NewPublicKey(k.pubKeyBytes(), k.param)
+}
+
+//String returns serialized extended key (xprv/xpub) using network HD version bytes
+func (k *ExtendedKey) String() string {
+	version := k.param.HDPublicKeyID
+	if k.isPrivate {
+		version = k.param.HDPrivateKeyID
+	}
+	buf := make([]byte, 0, serializedExtendedKeyLen)
+	buf = append(buf, version...)
+	buf = append(buf, k.depth)
+	buf = append(buf, k.parentFP...)
+	buf = append(buf, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(buf[len(buf)-4:], k.childNum)
+	buf = append(buf, k.chainCode...)
+	if k.isPrivate {
+		buf = append(buf, 0x0)
+		buf = paddedAppend(32, buf, k.key)
+	} else {
+		buf = append(buf, k.key...)
+	}
+	return base58.Encode(buf)
+}
+
+//ParseExtendedKey parses serialized xprv/xpub. Version bytes must match param.
+func ParseExtendedKey(key string, param *Params) (*ExtendedKey, error) {
+	pb, err := base58.Decode(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(pb) != serializedExtendedKeyLen {
+		return nil, ErrInvalidExtendedKey
+	}
+
+	version := pb[:4]
+	isPrivate := bytes.Equal(version, param.HDPrivateKeyID)
+	if !isPrivate && !bytes.Equal(version, param.HDPublicKeyID) {
+		return nil, ErrUnknownHDKeyID
+	}
+
+	k := &ExtendedKey{
+		depth:     pb[4],
+		parentFP:  pb[5:9],
+		childNum:  binary.BigEndian.Uint32(pb[9:13]),
+		chainCode: pb[13:45],
+		isPrivate: isPrivate,
+		param:     param,
+	}
+	if isPrivate {
+		if pb[45] != 0x0 {
+			return nil, ErrInvalidExtendedKey
+		}
+		n := new(big.Int).SetBytes(pb[46:])
+		if n.Sign() == 0 || n.Cmp(secp256k1.N) >= 0 {
+			return nil, ErrInvalidExtendedKey
+		}
+		k.key = pb[46:]
+	} else {
+		if _, err := btcec.ParsePubKey(pb[45:], secp256k1); err != nil {
+			return nil, err
+		}
+		k.key = pb[45:]
+	}
+	return k, nil
+}
+
+//ParseDerivationPath parses path like "m/44'/111'/0'/0/1" into child indexes.
+//Hardened indexes can be marked with ', h or H.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] == "m" || parts[0] == "M" {
+		parts = parts[1:]
+	}
+
+	var indexes []uint32
+	for _, p := range parts {
+		if p == "" {
+			return nil, ErrInvalidDerivationPath
+		}
+		var offset uint32
+		if last := p[len(p)-1]; last == '\'' || last == 'h' || last == 'H' {
+			offset = HardenedKeyStart
+			p = p[:len(p)-1]
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= HardenedKeyStart {
+			return nil, ErrInvalidDerivationPath
+		}
+		indexes = append(indexes, uint32(i)+offset)
+	}
+	return indexes, nil
+}
+
+//BIP44Path returns the BIP44 path m/44'/coin'/account'/change/index for network params
+func BIP44Path(param *Params, account, change, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/%d/%d", param.HDCoinType, account, change, index)
+}
+
+func hash160(data []byte) []byte {
+	h := sha256.Sum256(data)
+	ripeHash := ripemd160.New()
+	ripeHash.Write(h[:])
+	return ripeHash.Sum(nil)
+}
+
+//paddedAppend appends src to dst, left padded with zeros to size bytes
+func paddedAppend(size int, dst, src []byte) []byte {
+	for i := 0; i < size-len(src); i++ {
+		dst = append(dst, 0)
+	}
+	return append(dst, src...)
+}
@@ -0,0 +1,115 @@
+package arkcoin
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"testing"
+)
+
+//BIP32 test vector 1
+var hdVectors = []struct {
+	path string
+	xpub string
+	xprv string
+}{
+	{"m", "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+	{"m/0'", "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+	{"m/0'/1", "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs"},
+}
+
+func TestHDKeyVectors(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed, BitcoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range hdVectors {
+		key, err := master.Derive(v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.String() != v.xprv {
+			t.Errorf("%s wrong xprv: %s", v.path, key.String())
+		}
+		if key.Neuter().String() != v.xpub {
+			t.Errorf("%s wrong xpub: %s", v.path, key.Neuter().String())
+		}
+
+		parsed, err := ParseExtendedKey(v.xprv, BitcoinMain)
+		if err != nil || parsed.String() != v.xprv {
+			t.Errorf("%s parse xprv failed %v", v.path, err)
+		}
+	}
+}
+
+func TestHDPublicDerivation(t *testing.T) {
+	master, err := NewMasterKey(NewSeed("legal winner thank year wave sausage worth useful legal winner thank yellow", ""), ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := master.Derive("m/44'/111'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := ParseExtendedKey(account.Neuter().String(), ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xpub.IsPrivate() {
+		t.Error("xpub parsed as private key")
+	}
+	if _, err = xpub.Child(HardenedKeyStart); err != ErrDeriveHardenedFromPublic {
+		t.Error("hardened derivation from public key allowed")
+	}
+
+	for i := uint32(0); i < 3; i++ {
+		privChild, _ := master.Derive(BIP44Path(ArkCoinMain, 0, 0, i))
+		pubChild, _ := xpub.Derive(fmt.Sprintf("0/%d", i))
+
+		privKey, err := privChild.PrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, _ := pubChild.PublicKey()
+		if privKey.PublicKey.Address() != pubKey.Address() {
+			t.Error("private and public derivation do not match")
+		}
+
+		sig, _ := privKey.Sign(make([]byte, 32))
+		if err = pubKey.Verify(sig, make([]byte, 32)); err != nil {
+			t.Error(err.Error())
+		}
+		log.Println(t.Name(), BIP44Path(ArkCoinMain, 0, 0, i), pubKey.Address())
+	}
+}
+
+func TestNewSeed(t *testing.T) {
+	seed := NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	if hex.EncodeToString(seed) != "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04" {
+		t.Error("wrong seed", hex.EncodeToString(seed))
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/111h/0H/1/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{44 + HardenedKeyStart, 111 + HardenedKeyStart, HardenedKeyStart, 1, 2}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Error("wrong path index", i, path[i])
+		}
+	}
+
+	for _, p := range []string{"m/", "m/a", "m/1//2", "m/2147483648"} {
+		if _, err := ParseDerivationPath(p); err == nil {
+			t.Error("invalid path accepted", p)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package arkcoin
 import (
 	"crypto/sha256"
 	"errors"
-	"fmt"
 	"log"
 
 	"github.com/kristjank/ark-go/arkcoin/base58"
@@ -18,11 +17,13 @@ var (
 
 //Params is parameters of the coin.
 type Params struct {
+	Name                   string
 	DumpedPrivateKeyHeader []byte
 	AddressHeader          byte
 	P2SHHeader             byte
 	HDPrivateKeyID         []byte
 	HDPublicKeyID          []byte
+	HDCoinType             uint32 //BIP44 coin type
 }
 
 //PublicKey represents public key for bitcoin
@@ -61,6 +62,9 @@ func FromWIF(wif string, param *Params) (*PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(pb) != btcec.PrivKeyBytesLen+1 && len(pb) != btcec.PrivKeyBytesLen+2 {
+		return nil, errors.New("wif is invalid")
+	}
 	ok := false
 	for _, h := range param.DumpedPrivateKeyHeader {
 		if pb[0] == h {
@@ -138,15 +142,45 @@ func Generate(param *Params) (*PrivateKey, error) {
 	return key, nil
 }
 
-//Sign sign data.
+//Sign sign data. Returned DER signature is always canonical low-S.
 func (priv *PrivateKey) Sign(hash []byte) ([]byte, error) {
 	sig, err := priv.PrivateKey.Sign(hash)
 	if err != nil {
 		return nil, err
 	}
+	normalizeLowS(sig)
 	return sig.Serialize(), nil
 }
 
+//CompactSignatureLength is the length of recoverable signature - recovery id and 32 byte R and S
+const CompactSignatureLength = 65
+
+//ErrCompactSignatureLength is returned when compact signature is not CompactSignatureLength bytes long
+var ErrCompactSignatureLength = errors.New("compact signature must be 65 bytes long")
+
+//SignCompact signs hash and returns 65 byte compact signature with recovery id,
+//so the public key can be recovered with RecoverPublicKey
+func (priv *PrivateKey) SignCompact(hash []byte) ([]byte, error) {
+	return btcec.SignCompact(secp256k1, priv.PrivateKey, hash, priv.PublicKey.isCompressed)
+}
+
+//RecoverPublicKey recovers signer PublicKey and its address on network param from compact signature of hash
+func RecoverPublicKey(sig, hash []byte, param *Params) (*PublicKey, string, error) {
+	if len(sig) != CompactSignatureLength {
+		return nil, "", ErrCompactSignatureLength
+	}
+	key, isCompressed, err := btcec.RecoverCompact(secp256k1, sig, hash)
+	if err != nil {
+		return nil, "", err
+	}
+	pub := &PublicKey{
+		PublicKey:    key,
+		isCompressed: isCompressed,
+		param:        param,
+	}
+	return pub, pub.Address(), nil
+}
+
 //WIFAddress returns WIF format string from PrivateKey
 func (priv *PrivateKey) WIFAddress() string {
 	p := priv.Serialize()
@@ -175,9 +209,7 @@ func (pub *PublicKey) AddressBytes() []byte {
 	shadPublicKeyBytes := pub.Serialize()
 
 	ripeHash := ripemd160.New()
-	if _, err := ripeHash.Write(shadPublicKeyBytes[:]); err != nil {
-		log.Fatal(err)
-	}
+	ripeHash.Write(shadPublicKeyBytes[:]) //hash Write never returns an error
 	return ripeHash.Sum(nil)
 }
 
@@ -192,15 +224,16 @@ func (pub *PublicKey) Address() string {
 }
 
 //DecodeAddress converts bitcoin address to hex form.
+//Version byte is not checked, use ValidateAddress to check it against network params.
 func DecodeAddress(addr string) ([]byte, error) {
-	pb, err := base58.Decode(addr)
+	pb, err := decodeAddressBytes(addr)
 	if err != nil {
 		return nil, err
 	}
 	return pb[1:], nil
 }
 
-//Verify verifies signature is valid or not.
+//Verify verifies signature is valid or not. Parsing is lenient, use VerifyStrict for signatures from peers.
 func (pub *PublicKey) Verify(signature []byte, data []byte) error {
 	sig, err := btcec.ParseSignature(signature, secp256k1)
 	if err != nil {
@@ -208,7 +241,7 @@ func (pub *PublicKey) Verify(signature []byte, data []byte) error {
 	}
 	valid := sig.Verify(data, pub.PublicKey)
 	if !valid {
-		return fmt.Errorf("signature is invalid")
+		return ErrSignatureInvalid
 	}
 	return nil
 }
@@ -218,9 +251,7 @@ func AddressBytes(redeem []byte) []byte {
 	//h := sha256.Sum256(redeem)
 	h := redeem
 	ripeHash := ripemd160.New()
-	if _, err := ripeHash.Write(h[:]); err != nil {
-		log.Fatal(err)
-	}
+	ripeHash.Write(h[:]) //hash Write never returns an error
 	return ripeHash.Sum(nil)
 }
 
@@ -83,3 +83,39 @@ func TestSign(t *testing.T) {
 	}
 	log.Println(err)
 }
+
+func TestRecoverPublicKey(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	hash := make([]byte, 32)
+	hash[0] = 0x01
+
+	sig, err := key.SignCompact(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, addr, err := RecoverPublicKey(sig, hash, ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), addr)
+	if addr != key.PublicKey.Address() || hex.EncodeToString(pub.Serialize()) != hex.EncodeToString(key.PublicKey.Serialize()) {
+		t.Error("recovered wrong public key")
+	}
+
+	hash[1] = 0x01
+	if _, addr, err = RecoverPublicKey(sig, hash, ArkCoinMain); err == nil && addr == key.PublicKey.Address() {
+		t.Error("recovered signer for different hash")
+	}
+	if _, _, err = RecoverPublicKey(sig[1:], hash, ArkCoinMain); err != ErrCompactSignatureLength {
+		t.Error("expected ErrCompactSignatureLength, got", err)
+	}
+}
+
+func TestFromWIFInvalid(t *testing.T) {
+	key := NewPrivateKeyFromPassword("passphrase", ArkCoinMain)
+	for _, wif := range []string{"", key.PublicKey.Address(), "not a wif"} {
+		if _, err := FromWIF(wif, ArkCoinMain); err == nil {
+			t.Error("Invalid WIF accepted", wif)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package arkcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	//ErrMessageSignature is returned when signed message signature does not match message and public key
+	ErrMessageSignature = errors.New("message signature is invalid")
+	//ErrMessageSigner is returned when signed message public key does not match the expected public key or address
+	ErrMessageSigner = errors.New("message public key does not match the expected signer")
+)
+
+//SignedMessage is a message signed with private key, in the same JSON format
+//as the "sign message" feature of the Ark desktop wallet
+type SignedMessage struct {
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+	Message   string `json:"message"`
+}
+
+//SignMessage signs sha256 hash of message with priv and returns DER encoded signature in SignedMessage
+func SignMessage(priv *PrivateKey, message string) (*SignedMessage, error) {
+	sig, err := priv.Sign(messageHash(message))
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{
+		PublicKey: hex.EncodeToString(priv.PublicKey.Serialize()),
+		Signature: hex.EncodeToString(sig),
+		Message:   message,
+	}, nil
+}
+
+//ParseSignedMessage parses signed message JSON, as exported by Ark wallets
+func ParseSignedMessage(jsonMessage string) (*SignedMessage, error) {
+	var m SignedMessage
+	if err := json.Unmarshal([]byte(jsonMessage), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+//ToJSON returns signed message in Ark wallet JSON format
+func (m *SignedMessage) ToJSON() (string, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+//Verify checks the signature against message and the public key included in the signed message
+func (m *SignedMessage) Verify() error {
+	pub, err := m.publicKey(ActiveCoinConfig)
+	if err != nil {
+		return err
+	}
+	return m.verify(pub)
+}
+
+//VerifyPublicKey checks the message was signed by the owner of hex encoded public key pubKey
+func (m *SignedMessage) VerifyPublicKey(pubKey string) error {
+	pub, err := m.publicKey(ActiveCoinConfig)
+	if err != nil {
+		return err
+	}
+	expected, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pub.Serialize(), expected) {
+		return ErrMessageSigner
+	}
+	return m.verify(pub)
+}
+
+//VerifyAddress checks the message was signed by the owner of address addr on network param
+func (m *SignedMessage) VerifyAddress(addr string, param *Params) error {
+	if err := ValidateAddress(addr, param); err != nil {
+		return err
+	}
+	pub, err := m.publicKey(param)
+	if err != nil {
+		return err
+	}
+	if pub.Address() != addr {
+		return ErrMessageSigner
+	}
+	return m.verify(pub)
+}
+
+//Address returns the address of the signer on network param
+func (m *SignedMessage) Address(param *Params) (string, error) {
+	pub, err := m.publicKey(param)
+	if err != nil {
+		return "", err
+	}
+	return pub.Address(), nil
+}
+
+//SignMessageCompact signs sha256 hash of message and returns hex encoded compact signature.
+//Signer can be verified with VerifyMessageCompact using only the address, as public key is recovered.
+func SignMessageCompact(priv *PrivateKey, message string) (string, error) {
+	sig, err := priv.SignCompact(messageHash(message))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig), nil
+}
+
+//VerifyMessageCompact checks hex encoded compact signature of message was created by the owner of address addr
+func VerifyMessageCompact(addr, signature, message string, param *Params) error {
+	if err := ValidateAddress(addr, param); err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	_, signer, err := RecoverPublicKey(sig, messageHash(message), param)
+	if err != nil {
+		return ErrMessageSignature
+	}
+	if signer != addr {
+		return ErrMessageSigner
+	}
+	return nil
+}
+
+func (m *SignedMessage) publicKey(param *Params) (*PublicKey, error) {
+	pb, err := hex.DecodeString(m.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewPublicKey(pb, param)
+}
+
+func (m *SignedMessage) verify(pub *PublicKey) error {
+	sig, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return err
+	}
+	if pub.Verify(sig, messageHash(m.Message)) != nil {
+		return ErrMessageSignature
+	}
+	return nil
+}
+
+func messageHash(message string) []byte {
+	h := sha256.Sum256([]byte(message))
+	return h[:]
+}
@@ -0,0 +1,77 @@
+package arkcoin
+
+import (
+	"log"
+	"testing"
+)
+
+func TestSignMessage(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	msg, err := SignMessage(key, "I own this address")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonMsg, err := msg.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), jsonMsg)
+
+	parsed, err := ParseSignedMessage(jsonMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = parsed.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = parsed.VerifyPublicKey(msg.PublicKey); err != nil {
+		t.Error(err.Error())
+	}
+	if err = parsed.VerifyAddress(key.PublicKey.Address(), ArkCoinMain); err != nil {
+		t.Error(err.Error())
+	}
+	if addr, _ := parsed.Address(ArkCoinMain); addr != key.PublicKey.Address() {
+		t.Error("wrong signer address", addr)
+	}
+}
+
+func TestVerifyMessageErrors(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	other := NewPrivateKeyFromPassword("another passphrase", ArkCoinMain)
+	msg, _ := SignMessage(key, "I own this address")
+
+	tampered := *msg
+	tampered.Message = "I own this address too"
+	if err := tampered.Verify(); err != ErrMessageSignature {
+		t.Error("expected ErrMessageSignature, got", err)
+	}
+
+	if err := msg.VerifyAddress(other.PublicKey.Address(), ArkCoinMain); err != ErrMessageSigner {
+		t.Error("expected ErrMessageSigner, got", err)
+	}
+
+	otherMsg, _ := SignMessage(other, "")
+	if err := msg.VerifyPublicKey(otherMsg.PublicKey); err != ErrMessageSigner {
+		t.Error("expected ErrMessageSigner, got", err)
+	}
+
+	devnetKey := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinDevTest)
+	if _, ok := msg.VerifyAddress(devnetKey.PublicKey.Address(), ArkCoinMain).(AddressNetworkError); !ok {
+		t.Error("expected AddressNetworkError")
+	}
+}
+
+func TestVerifyMessageCompact(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	sig, err := SignMessageCompact(key, "I own this address")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyMessageCompact(key.PublicKey.Address(), sig, "I own this address", ArkCoinMain); err != nil {
+		t.Error(err.Error())
+	}
+	if err = VerifyMessageCompact(key.PublicKey.Address(), sig, "I own another address", ArkCoinMain); err != ErrMessageSigner {
+		t.Error("expected ErrMessageSigner, got", err)
+	}
+}
@@ -0,0 +1,224 @@
+package arkcoin
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	//ErrEntropyLength is returned when entropy is not 128-256 bits in 32 bit steps
+	ErrEntropyLength = errors.New("entropy length must be [128, 256] and a multiple of 32")
+	//ErrMnemonicWordCount is returned when mnemonic does not have 12, 15, 18, 21 or 24 words
+	ErrMnemonicWordCount = errors.New("mnemonic must have 12, 15, 18, 21 or 24 words")
+	//ErrMnemonicChecksum is returned when all words are known, but the checksum does not match
+	ErrMnemonicChecksum = errors.New("mnemonic checksum is invalid")
+)
+
+//UnknownWordError is returned when a mnemonic word is not in the BIP39 wordlist.
+//Suggestions holds the closest wordlist entries, to help with typos.
+type UnknownWordError struct {
+	Word        string
+	Position    int
+	Suggestions []string
+}
+
+//Error is to implement Error interface.
+func (e UnknownWordError) Error() string {
+	msg := fmt.Sprintf("unknown mnemonic word %q at position %d", e.Word, e.Position+1)
+	if len(e.Suggestions) > 0 {
+		msg += ", did you mean: " + strings.Join(e.Suggestions, ", ")
+	}
+	return msg
+}
+
+var wordIndex map[string]int
+
+func init() {
+	wordIndex = make(map[string]int, len(englishWordList))
+	for i, w := range englishWordList {
+		wordIndex[w] = i
+	}
+}
+
+//NewEntropy returns random entropy of bitSize bits, to be used for mnemonic creation.
+func NewEntropy(bitSize int) ([]byte, error) {
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return nil, ErrEntropyLength
+	}
+	entropy := make([]byte, bitSize/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+//NewMnemonic generates a random BIP39 english mnemonic with wordCount words (12 or 24 are common).
+//The result can be used as passphrase in NewPrivateKeyFromPassword.
+func NewMnemonic(wordCount int) (string, error) {
+	if wordCount < 12 || wordCount > 24 || wordCount%3 != 0 {
+		return "", ErrMnemonicWordCount
+	}
+	entropy, err := NewEntropy(wordCount / 3 * 32)
+	if err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy)
+}
+
+//MnemonicFromEntropy returns the BIP39 english mnemonic for entropy
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bitSize := len(entropy) * 8
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return "", ErrEntropyLength
+	}
+	checksumBits := bitSize / 32
+	hash := sha256.Sum256(entropy)
+
+	//entropy with appended checksum bits, read in 11 bit groups
+	data := append(append([]byte{}, entropy...), hash[0])
+	words := make([]string, (bitSize+checksumBits)/11)
+	for i := range words {
+		words[i] = englishWordList[readBits(data, i*11, 11)]
+	}
+	return strings.Join(words, " "), nil
+}
+
+//ValidateMnemonic checks word count, wordlist membership and checksum of a mnemonic.
+//An UnknownWordError with suggestions is returned for misspelled words.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicToEntropy(mnemonic)
+	return err
+}
+
+//NormalizeMnemonic returns mnemonic in lowercase with words separated by single spaces.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+//NewPrivateKeyFromMnemonic validates the mnemonic and creates the PrivateKey from its
+//normalized form, same as NewPrivateKeyFromPassword does for the passphrase.
+func NewPrivateKeyFromMnemonic(mnemonic string, param *Params) (*PrivateKey, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyFromPassword(NormalizeMnemonic(mnemonic), param), nil
+}
+
+//NewSeed returns the 64 byte BIP39 seed for mnemonic and optional password,
+//to be used with NewMasterKey for hierarchical deterministic keys.
+func NewSeed(mnemonic, password string) []byte {
+	return pbkdf2.Key([]byte(NormalizeMnemonic(mnemonic)), []byte("mnemonic"+password), 2048, 64, sha512.New)
+}
+
+func mnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrMnemonicWordCount
+	}
+
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		ix, ok := wordIndex[w]
+		if !ok {
+			return nil, UnknownWordError{Word: w, Position: i, Suggestions: SuggestWords(w)}
+		}
+		writeBits(data, i*11, 11, ix)
+	}
+
+	checksumBits := len(words) * 11 / 33
+	entropy := data[:(len(words)*11-checksumBits)/8]
+	hash := sha256.Sum256(entropy)
+	if readBits(data, len(entropy)*8, checksumBits) != readBits(hash[:], 0, checksumBits) {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+//SuggestWords returns up to 3 wordlist entries closest to word.
+//Words sharing the first 4 letters are preferred, as they are unique in the BIP39 wordlist.
+func SuggestWords(word string) []string {
+	type candidate struct {
+		word     string
+		distance int
+	}
+	word = strings.ToLower(word)
+	var candidates []candidate
+	for _, w := range englishWordList {
+		d := levenshtein(word, w)
+		if len(word) >= 4 && strings.HasPrefix(w, word[:4]) {
+			d = 0
+		}
+		if d <= 2 {
+			candidates = append(candidates, candidate{w, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		suggestions = append(suggestions, candidates[i].word)
+	}
+	return suggestions
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+//readBits reads n bits (n <= 11) from data starting at bit offset, big endian
+func readBits(data []byte, offset, n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		bit := offset + i
+		v <<= 1
+		if data[bit/8]&(0x80>>uint(bit%8)) != 0 {
+			v |= 1
+		}
+	}
+	return v
+}
+
+//writeBits writes lower n bits of v into data starting at bit offset, big endian
+func writeBits(data []byte, offset, n, v int) {
+	for i := 0; i < n; i++ {
+		bit := offset + i
+		if v&(1<<uint(n-1-i)) != 0 {
+			data[bit/8] |= 0x80 >> uint(bit%8)
+		}
+	}
+}
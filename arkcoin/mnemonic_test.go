@@ -0,0 +1,91 @@
+package arkcoin
+
+import (
+	"encoding/hex"
+	"log"
+	"strings"
+	"testing"
+)
+
+var mnemonicVectors = []struct {
+	entropy  string
+	mnemonic string
+}{
+	{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+	{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+	{"80808080808080808080808080808080", "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+	{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+	{"0000000000000000000000000000000000000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"},
+	{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote"},
+}
+
+func TestMnemonicFromEntropy(t *testing.T) {
+	for _, v := range mnemonicVectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		mnemonic, err := MnemonicFromEntropy(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != v.mnemonic {
+			t.Errorf("wrong mnemonic for %s: %s", v.entropy, mnemonic)
+		}
+		if err = ValidateMnemonic(v.mnemonic); err != nil {
+			t.Errorf("valid mnemonic rejected: %s", err.Error())
+		}
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, count := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(strings.Fields(mnemonic)) != count {
+			t.Errorf("expected %d words, got: %s", count, mnemonic)
+		}
+		if err = ValidateMnemonic(mnemonic); err != nil {
+			t.Error(err.Error())
+		}
+		log.Println(t.Name(), mnemonic)
+	}
+
+	if _, err := NewMnemonic(13); err != ErrMnemonicWordCount {
+		t.Error("expected ErrMnemonicWordCount")
+	}
+}
+
+func TestValidateMnemonicErrors(t *testing.T) {
+	if err := ValidateMnemonic("legal winner thank year wave sausage worth useful legal winner thank thank"); err != ErrMnemonicChecksum {
+		t.Error("expected ErrMnemonicChecksum, got", err)
+	}
+
+	if err := ValidateMnemonic("abandon abandon abandon"); err != ErrMnemonicWordCount {
+		t.Error("expected ErrMnemonicWordCount, got", err)
+	}
+
+	err := ValidateMnemonic("legal winner thank year wave sausage worth usefull legal winner thank yellow")
+	wordErr, ok := err.(UnknownWordError)
+	if !ok {
+		t.Fatal("expected UnknownWordError, got", err)
+	}
+	if wordErr.Position != 7 || len(wordErr.Suggestions) == 0 || wordErr.Suggestions[0] != "useful" {
+		t.Error("wrong unknown word details", wordErr)
+	}
+	log.Println(t.Name(), err.Error())
+}
+
+func TestNewPrivateKeyFromMnemonic(t *testing.T) {
+	mnemonic := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	key, err := NewPrivateKeyFromMnemonic("  Legal winner thank year wave sausage worth useful legal winner thank yellow ", ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.PublicKey.Address() != NewPrivateKeyFromPassword(mnemonic, ArkCoinMain).PublicKey.Address() {
+		t.Error("mnemonic key does not match passphrase key")
+	}
+
+	if _, err = NewPrivateKeyFromMnemonic("this is a top secret passphrase", ArkCoinMain); err == nil {
+		t.Error("invalid mnemonic accepted")
+	}
+}
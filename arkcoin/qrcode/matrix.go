@@ -0,0 +1,260 @@
+package qrcode
+
+//matrix is the symbol being built. Function modules (finder, timing, alignment,
+//format and version areas) are marked in reserved and skipped by data placement and masking.
+type matrix struct {
+	size     int
+	modules  []bool
+	reserved []bool
+}
+
+func newMatrix(version int) *matrix {
+	size := 17 + 4*version
+	m := &matrix{size: size, modules: make([]bool, size*size), reserved: make([]bool, size*size)}
+
+	for i := 0; i < size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(size-4, 3)
+	m.drawFinder(3, size-4)
+
+	centers := alignmentTable[version-1]
+	last := len(centers) - 1
+	for i, cy := range centers {
+		for j, cx := range centers {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue //overlaps finder pattern
+			}
+			m.drawAlignment(cx, cy)
+		}
+	}
+
+	//reserve format areas with placeholder values, drawn by drawFormat
+	m.drawFormat(Low, 0)
+	if version >= 7 {
+		m.drawVersion(version)
+	}
+	return m
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y*m.size+x] = dark
+	m.reserved[y*m.size+x] = true
+}
+
+//drawFinder draws finder pattern with separator centered at x, y
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= m.size || yy >= m.size {
+				continue
+			}
+			d := abs(dx)
+			if abs(dy) > d {
+				d = abs(dy)
+			}
+			m.setFunction(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+//drawAlignment draws 5x5 alignment pattern centered at x, y
+func (m *matrix) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			d := abs(dx)
+			if abs(dy) > d {
+				d = abs(dy)
+			}
+			m.setFunction(x+dx, y+dy, d != 1)
+		}
+	}
+}
+
+//drawFormat draws both copies of 15 bit format information and the dark module
+func (m *matrix) drawFormat(level Level, mask int) {
+	data := levelFormatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+//drawVersion draws both copies of 18 bit version information (versions 7 and up)
+func (m *matrix) drawVersion(version int) {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1f25
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 != 0
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+//placeData fills non function modules with codewords in the zigzag order, two columns at a time
+func (m *matrix) placeData(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 //skip vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.reserved[y*m.size+x] {
+					continue
+				}
+				//remainder bits stay light
+				if i < len(codewords)*8 {
+					m.modules[y*m.size+x] = codewords[i/8]>>uint(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+//maskBit reports whether mask pattern inverts module at x, y
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+//applyMask inverts data modules selected by mask, applying it twice restores the modules
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.reserved[y*m.size+x] && maskBit(mask, x, y) {
+				m.modules[y*m.size+x] = !m.modules[y*m.size+x]
+			}
+		}
+	}
+}
+
+//penalty scores the symbol with the four mask evaluation rules, lower is better
+func (m *matrix) penalty() int {
+	at := func(x, y int) bool { return m.modules[y*m.size+x] }
+	score := 0
+
+	//rule 1 - runs of five or more same color modules, rule 3 - finder like patterns
+	for _, vertical := range []bool{false, true} {
+		for a := 0; a < m.size; a++ {
+			get := func(b int) bool {
+				if vertical {
+					return at(a, b)
+				}
+				return at(b, a)
+			}
+			run := 1
+			for b := 1; b < m.size; b++ {
+				if get(b) == get(b-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			for b := 0; b+11 <= m.size; b++ {
+				if matchPattern(get, b, finderLike1) || matchPattern(get, b, finderLike2) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	//rule 2 - 2x2 blocks of same color
+	for y := 0; y+1 < m.size; y++ {
+		for x := 0; x+1 < m.size; x++ {
+			c := at(x, y)
+			if c == at(x+1, y) && c == at(x, y+1) && c == at(x+1, y+1) {
+				score += 3
+			}
+		}
+	}
+
+	//rule 4 - balance of dark and light modules
+	dark := 0
+	for _, d := range m.modules {
+		if d {
+			dark++
+		}
+	}
+	percent := dark * 100 / len(m.modules)
+	score += abs(percent-50) / 5 * 10
+	return score
+}
+
+var (
+	finderLike1 = []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderLike2 = []bool{false, false, false, false, true, false, true, true, true, false, true}
+)
+
+func matchPattern(get func(int) bool, start int, pattern []bool) bool {
+	for i, p := range pattern {
+		if get(start+i) != p {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,215 @@
+//Package qrcode is a pure Go QR code encoder for byte mode data (ISO/IEC 18004),
+//enough for addresses, WIF keys and payment URIs. Codes render to SVG and images.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+//Level is the error correction level
+type Level int
+
+const (
+	//Low recovers about 7% of damaged codewords
+	Low Level = iota
+	//Medium recovers about 15% of damaged codewords
+	Medium
+	//Quartile recovers about 25% of damaged codewords
+	Quartile
+	//High recovers about 30% of damaged codewords
+	High
+)
+
+//QuietZone is the light border width in modules added by SVG and Image
+const QuietZone = 4
+
+var (
+	//ErrTooLong is returned when data does not fit the largest supported version at requested level
+	ErrTooLong = errors.New("qrcode: data too long")
+	//ErrLevel is returned for unknown error correction level
+	ErrLevel = errors.New("qrcode: invalid error correction level")
+)
+
+//Code is an encoded QR code symbol
+type Code struct {
+	Size    int //modules per side
+	Version int
+	Level   Level
+	Mask    int
+	modules []bool //dark modules, row by row
+}
+
+//Encode encodes data in byte mode using the smallest version that fits at level
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, ErrLevel
+	}
+	version := 0
+	for v := 1; v <= MaxVersion; v++ {
+		if 4+countBits(v)+8*len(data) <= 8*blockTable[v-1][level].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addErrorCorrection(dataCodewords(data, version, level), version, level)
+
+	m := newMatrix(version)
+	m.placeData(codewords)
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormat(level, mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) //masking twice restores data
+	}
+	m.applyMask(best)
+	m.drawFormat(level, best)
+
+	return &Code{Size: m.size, Version: version, Level: level, Mask: best, modules: m.modules}, nil
+}
+
+//EncodeString encodes text in byte mode
+func EncodeString(text string, level Level) (*Code, error) {
+	return Encode([]byte(text), level)
+}
+
+//Black reports whether module at column x and row y is dark. Modules outside of the symbol are light.
+func (c *Code) Black(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y*c.Size+x]
+}
+
+//Path returns SVG path data drawing dark modules as unit squares, without the quiet zone.
+//Scale it with a transform to the wanted size.
+func (c *Code) Path() string {
+	var b strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			run := 1
+			for c.Black(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", x, y, run, run)
+			x += run
+		}
+	}
+	return b.String()
+}
+
+//SVG returns standalone SVG document of the code with quiet zone, moduleSize pixels per module
+func (c *Code) SVG(moduleSize int) string {
+	side := c.Size + 2*QuietZone
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path transform="translate(%d %d)" fill="#000" d="%s"/></svg>`,
+		side*moduleSize, side*moduleSize, side, side, side, side, QuietZone, QuietZone, c.Path())
+}
+
+//Image returns grayscale image of the code with quiet zone, moduleSize pixels per module
+func (c *Code) Image(moduleSize int) *image.Gray {
+	if moduleSize < 1 {
+		moduleSize = 1
+	}
+	side := (c.Size + 2*QuietZone) * moduleSize
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			shade := color.Gray{Y: 0xff}
+			if c.Black(x/moduleSize-QuietZone, y/moduleSize-QuietZone) {
+				shade = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, shade)
+		}
+	}
+	return img
+}
+
+//countBits returns length of byte mode character count indicator
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+//dataCodewords builds byte mode bit stream - mode, count, data, terminator and padding
+func dataCodewords(data []byte, version int, level Level) []byte {
+	capacity := blockTable[version-1][level].dataCodewords()
+	bits := &bitBuffer{}
+	bits.append(0x4, 4)
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	terminator := 8*capacity - bits.length
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-bits.length%8)%8)
+	for pad := 0; len(bits.bytes) < capacity; pad++ {
+		bits.append([]int{0xec, 0x11}[pad%2], 8)
+	}
+	return bits.bytes
+}
+
+//addErrorCorrection splits data into blocks, adds error correction codewords and interleaves them
+func addErrorCorrection(data []byte, version int, level Level) []byte {
+	info := blockTable[version-1][level]
+	gen := rsGenerator(info.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	for i, offset := 0, 0; i < info.blocks1+info.blocks2; i++ {
+		n := info.data1
+		if i >= info.blocks1 {
+			n++
+		}
+		block := data[offset : offset+n]
+		offset += n
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, gen))
+	}
+
+	result := make([]byte, 0, len(data)+len(dataBlocks)*info.ecPerBlock)
+	for i := 0; i <= info.data1; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+type bitBuffer struct {
+	bytes  []byte
+	length int
+}
+
+func (b *bitBuffer) append(value, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if b.length%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>uint(i)&1 != 0 {
+			b.bytes[b.length/8] |= 0x80 >> uint(b.length%8)
+		}
+		b.length++
+	}
+}
@@ -0,0 +1,217 @@
+package qrcode
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	//HELLO WORLD 1-M example from the QR code tutorial at thonky.com
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if ec := rsRemainder(data, rsGenerator(10)); !bytes.Equal(ec, expected) {
+		t.Error("wrong error correction codewords", ec)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	m := newMatrix(7)
+	m.drawFormat(Medium, 0)
+	//format bits 101010000010010 for level M, mask 0 - bit 14 first, from column 0 on row 8
+	expected := "101010000010010"
+	var got string
+	for _, x := range []int{0, 1, 2, 3, 4, 5, 7} {
+		got += bitString(m.modules[8*m.size+x])
+	}
+	for _, y := range []int{8, 7, 5, 4, 3, 2, 1, 0} {
+		got += bitString(m.modules[y*m.size+8])
+	}
+	if got != expected {
+		t.Error("wrong format bits", got)
+	}
+
+	//version 7 information 000111110010010100, bit 0 at bottom left corner of the top right block
+	expected = "000111110010010100"
+	got = ""
+	for i := 17; i >= 0; i-- {
+		got += bitString(m.modules[(i/3)*m.size+m.size-11+i%3])
+	}
+	if got != expected {
+		t.Error("wrong version bits", got)
+	}
+}
+
+func bitString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func TestEncodeDecode(t *testing.T) {
+	cases := []string{
+		"",
+		"AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25",
+		"SCVsTyaGXBFn1WJtvcfW81vzY9Tp7AUhh6eLwAh6zNM3SuDpcbEB",
+		"ark:AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25?amount=1.5&vendorField=payout%20reference",
+		strings.Repeat("x", 200),
+	}
+	for _, text := range cases {
+		for level := Low; level <= High; level++ {
+			code, err := EncodeString(text, level)
+			if err == ErrTooLong {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decode(code)
+			if err != nil {
+				t.Error(text, level, err)
+				continue
+			}
+			if string(decoded) != text {
+				t.Error("decoded text differs", string(decoded))
+			}
+		}
+	}
+
+	code, _ := EncodeString(cases[2], Medium)
+	log.Println(t.Name(), "WIF encoded to version", code.Version, "mask", code.Mask)
+	if code.Version != 4 {
+		t.Error("WIF should fit version 4 at medium level, got", code.Version)
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(make([]byte, 272), Low); err != ErrTooLong {
+		t.Error("expected ErrTooLong", err)
+	}
+	if _, err := Encode(make([]byte, 271), Low); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSVGAndImage(t *testing.T) {
+	code, err := EncodeString("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25", Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := code.Image(3)
+	side := (code.Size + 2*QuietZone) * 3
+	if img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Error("wrong image size", img.Bounds())
+	}
+	//top left module of finder pattern is dark, quiet zone is light
+	if img.GrayAt(QuietZone*3, QuietZone*3).Y != 0 || img.GrayAt(0, 0).Y != 0xff {
+		t.Error("wrong image modules")
+	}
+	if svg := code.SVG(4); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "M0 0h7v1h-7z") {
+		t.Error("wrong svg", svg)
+	}
+}
+
+//decode reads byte mode data back from the code, checking format information and error correction
+func decode(c *Code) ([]byte, error) {
+	m := newMatrix(c.Version)
+	copy(m.modules, c.modules)
+	reserved := m.reserved
+
+	//read format information from around the top left finder and compare with encoded level and mask
+	check := newMatrix(c.Version)
+	check.drawFormat(c.Level, c.Mask)
+	for i := range reserved {
+		if reserved[i] && check.modules[i] != m.modules[i] {
+			return nil, errorString("function pattern or format information differs")
+		}
+	}
+
+	m.applyMask(c.Mask)
+	var codewords []byte
+	bits := 0
+	var current byte
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if reserved[y*m.size+x] {
+					continue
+				}
+				current <<= 1
+				if m.modules[y*m.size+x] {
+					current |= 1
+				}
+				bits++
+				if bits%8 == 0 {
+					codewords = append(codewords, current)
+					current = 0
+				}
+			}
+		}
+	}
+
+	info := blockTable[c.Version-1][c.Level]
+	blocks := info.blocks1 + info.blocks2
+	dataBlocks := make([][]byte, blocks)
+	pos := 0
+	for i := 0; i <= info.data1; i++ {
+		for b := 0; b < blocks; b++ {
+			if i < info.data1 || b >= info.blocks1 {
+				dataBlocks[b] = append(dataBlocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	gen := rsGenerator(info.ecPerBlock)
+	for i := 0; i < info.ecPerBlock; i++ {
+		for b := 0; b < blocks; b++ {
+			if rsRemainder(dataBlocks[b], gen)[i] != codewords[pos] {
+				return nil, errorString("error correction codeword mismatch")
+			}
+			pos++
+		}
+	}
+
+	var data []byte
+	for _, block := range dataBlocks {
+		data = append(data, block...)
+	}
+	if data[0]>>4 != 0x4 {
+		return nil, errorString("not byte mode")
+	}
+	reader := &bitReader{data: data, pos: 4}
+	n := reader.read(countBits(c.Version))
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte(reader.read(8))
+	}
+	return result, nil
+}
+
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(bits int) int {
+	v := 0
+	for i := 0; i < bits; i++ {
+		v = v<<1 | int(r.data[r.pos/8]>>uint(7-r.pos%8)&1)
+		r.pos++
+	}
+	return v
+}
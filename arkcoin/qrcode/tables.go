@@ -0,0 +1,48 @@
+package qrcode
+
+//MaxVersion is the largest symbol version supported by Encode (57x57 modules)
+const MaxVersion = 10
+
+//blockInfo describes error correction blocks of a version and level.
+//Second group blocks hold one data codeword more than the first group.
+type blockInfo struct {
+	ecPerBlock int
+	blocks1    int
+	data1      int
+	blocks2    int
+}
+
+func (b blockInfo) dataCodewords() int {
+	return b.blocks1*b.data1 + b.blocks2*(b.data1+1)
+}
+
+//blockTable is indexed by version-1 and Level (L, M, Q, H)
+var blockTable = [MaxVersion][4]blockInfo{
+	{{7, 1, 19, 0}, {10, 1, 16, 0}, {13, 1, 13, 0}, {17, 1, 9, 0}},
+	{{10, 1, 34, 0}, {16, 1, 28, 0}, {22, 1, 22, 0}, {28, 1, 16, 0}},
+	{{15, 1, 55, 0}, {26, 1, 44, 0}, {18, 2, 17, 0}, {22, 2, 13, 0}},
+	{{20, 1, 80, 0}, {18, 2, 32, 0}, {26, 2, 24, 0}, {16, 4, 9, 0}},
+	{{26, 1, 108, 0}, {24, 2, 43, 0}, {18, 2, 15, 2}, {22, 2, 11, 2}},
+	{{18, 2, 68, 0}, {16, 4, 27, 0}, {24, 4, 19, 0}, {28, 4, 15, 0}},
+	{{20, 2, 78, 0}, {18, 4, 31, 0}, {18, 2, 14, 4}, {26, 4, 13, 1}},
+	{{24, 2, 97, 0}, {22, 2, 38, 2}, {22, 4, 18, 2}, {26, 4, 14, 2}},
+	{{30, 2, 116, 0}, {22, 3, 36, 2}, {20, 4, 16, 4}, {24, 4, 12, 4}},
+	{{18, 2, 68, 2}, {26, 4, 43, 1}, {24, 6, 19, 2}, {28, 6, 15, 2}},
+}
+
+//alignmentTable holds alignment pattern center coordinates, indexed by version-1
+var alignmentTable = [MaxVersion][]int{
+	nil,
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+//format information error correction level bits, indexed by Level
+var levelFormatBits = [4]int{1, 0, 3, 2}
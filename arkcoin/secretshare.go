@@ -0,0 +1,243 @@
+package arkcoin
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin/base58"
+	"github.com/kristjank/ark-go/arkcoin/shamir"
+)
+
+//SecretKind tells what was split into shares
+type SecretKind byte
+
+const (
+	//SecretPassphrase shares hold the account passphrase
+	SecretPassphrase SecretKind = iota
+	//SecretPrivateKey shares hold the 32 byte private key
+	SecretPrivateKey
+)
+
+//secretShareVersion is the first byte of serialized share
+const secretShareVersion = 1
+
+//secretShareHeaderLen is version(1) id(2) threshold(1) index(1) kind(1) secret length(1)
+const secretShareHeaderLen = 7
+
+var (
+	//ErrShareFormat is returned when share can not be decoded
+	ErrShareFormat = errors.New("share is not valid")
+	//ErrShareChecksum is returned when share words checksum does not match
+	ErrShareChecksum = errors.New("share checksum mismatch")
+	//ErrShareMismatch is returned when combined shares belong to different splits
+	ErrShareMismatch = errors.New("shares belong to different secrets")
+	//ErrShareThreshold is returned when fewer shares than the threshold are combined
+	ErrShareThreshold = errors.New("not enough shares to rebuild the secret")
+	//ErrShareKind is returned when passphrase is requested from private key shares
+	ErrShareKind = errors.New("shares do not hold a passphrase")
+	//ErrSecretLength is returned when secret is too long for a share
+	ErrSecretLength = errors.New("secret must be 1 to 255 bytes long")
+)
+
+//SecretShare is one part of a passphrase or private key split with Shamir secret sharing.
+//ID is random and the same for all shares of one split. Index is the share x coordinate.
+type SecretShare struct {
+	ID        uint16
+	Threshold byte
+	Index     byte
+	Kind      SecretKind
+	Data      []byte
+	secretLen byte
+}
+
+//SplitPassphrase splits passphrase into parts shares, any threshold of them rebuild it
+func SplitPassphrase(passphrase string, parts, threshold int) ([]*SecretShare, error) {
+	return splitSecret([]byte(passphrase), SecretPassphrase, parts, threshold)
+}
+
+//SplitPrivateKey splits private key into parts shares, any threshold of them rebuild it
+func SplitPrivateKey(priv *PrivateKey, parts, threshold int) ([]*SecretShare, error) {
+	return splitSecret(paddedAppend(32, nil, priv.D.Bytes()), SecretPrivateKey, parts, threshold)
+}
+
+func splitSecret(secret []byte, kind SecretKind, parts, threshold int) ([]*SecretShare, error) {
+	//checksum is split with the secret, so combining wrong shares is detected
+	if len(secret) == 0 || len(secret) > 255 {
+		return nil, ErrSecretLength
+	}
+	hash := sha256.Sum256(secret)
+	split, err := shamir.Split(append(append([]byte{}, secret...), hash[:4]...), parts, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	var id [2]byte
+	if _, err = rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+
+	shares := make([]*SecretShare, len(split))
+	for i, s := range split {
+		shares[i] = &SecretShare{
+			ID:        binary.BigEndian.Uint16(id[:]),
+			Threshold: byte(threshold),
+			Index:     s[len(s)-1],
+			Kind:      kind,
+			Data:      s[:len(s)-1],
+			secretLen: byte(len(secret)),
+		}
+	}
+	return shares, nil
+}
+
+//CombinePassphrase rebuilds passphrase from passphrase shares
+func CombinePassphrase(shares []*SecretShare) (string, error) {
+	kind, secret, err := combineShares(shares)
+	if err != nil {
+		return "", err
+	}
+	if kind != SecretPassphrase {
+		return "", ErrShareKind
+	}
+	return string(secret), nil
+}
+
+//CombinePrivateKey rebuilds the PrivateKey from passphrase or private key shares
+func CombinePrivateKey(shares []*SecretShare, param *Params) (*PrivateKey, error) {
+	kind, secret, err := combineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	if kind == SecretPassphrase {
+		return NewPrivateKeyFromPassword(string(secret), param), nil
+	}
+	return NewPrivateKey(secret, param), nil
+}
+
+func combineShares(shares []*SecretShare) (SecretKind, []byte, error) {
+	if len(shares) == 0 {
+		return 0, nil, ErrShareThreshold
+	}
+	first := shares[0]
+	if len(shares) < int(first.Threshold) {
+		return 0, nil, ErrShareThreshold
+	}
+
+	parts := make([][]byte, len(shares))
+	for i, s := range shares {
+		if s.ID != first.ID || s.Threshold != first.Threshold || s.Kind != first.Kind || s.secretLen != first.secretLen {
+			return 0, nil, ErrShareMismatch
+		}
+		parts[i] = append(append([]byte{}, s.Data...), s.Index)
+	}
+
+	combined, err := shamir.Combine(parts)
+	if err != nil {
+		return 0, nil, err
+	}
+	secret := combined[:len(combined)-4]
+	hash := sha256.Sum256(secret)
+	if !bytes.Equal(hash[:4], combined[len(combined)-4:]) {
+		return 0, nil, ErrShareMismatch
+	}
+	return first.Kind, secret, nil
+}
+
+func (s *SecretShare) bytes() []byte {
+	b := make([]byte, secretShareHeaderLen, secretShareHeaderLen+len(s.Data)+4)
+	b[0] = secretShareVersion
+	binary.BigEndian.PutUint16(b[1:3], s.ID)
+	b[3] = s.Threshold
+	b[4] = s.Index
+	b[5] = byte(s.Kind)
+	b[6] = s.secretLen
+	return append(b, s.Data...)
+}
+
+//Base58 returns the share encoded as base58check string
+func (s *SecretShare) Base58() string {
+	return base58.Encode(s.bytes())
+}
+
+//Words returns the share encoded as BIP39 english words, last 4 bytes are the checksum
+func (s *SecretShare) Words() string {
+	b := s.bytes()
+	hash := sha256.Sum256(b)
+	hash = sha256.Sum256(hash[:])
+	b = append(b, hash[:4]...)
+
+	words := make([]string, (len(b)*8+10)/11)
+	//zero padding, so the last partial word can be read
+	padded := append(b, 0, 0)
+	for i := range words {
+		words[i] = englishWordList[readBits(padded, i*11, 11)]
+	}
+	return strings.Join(words, " ")
+}
+
+//String returns the share description, without the share data
+func (s *SecretShare) String() string {
+	return fmt.Sprintf("share #%d (%d required), id %04x", s.Index, s.Threshold, s.ID)
+}
+
+//ParseSecretShare parses share encoded with Base58 or Words
+func ParseSecretShare(encoded string) (*SecretShare, error) {
+	words := strings.Fields(strings.ToLower(encoded))
+	if len(words) == 1 {
+		b, err := base58.Decode(strings.TrimSpace(encoded))
+		if err == base58.ErrChecksum {
+			return nil, ErrShareChecksum
+		}
+		if err != nil {
+			return nil, ErrShareFormat
+		}
+		return parseShareBytes(b)
+	}
+
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		ix, ok := wordIndex[w]
+		if !ok {
+			return nil, UnknownWordError{Word: w, Position: i, Suggestions: SuggestWords(w)}
+		}
+		writeBits(data, i*11, 11, ix)
+	}
+	if len(data) < secretShareHeaderLen+4 {
+		return nil, ErrShareFormat
+	}
+	length := secretShareHeaderLen + int(data[6]) + 4 + 4
+	if length > len(data) || (length*8+10)/11 != len(words) {
+		return nil, ErrShareFormat
+	}
+	b, checksum := data[:length-4], data[length-4:length]
+	hash := sha256.Sum256(b)
+	hash = sha256.Sum256(hash[:])
+	if !bytes.Equal(hash[:4], checksum) {
+		return nil, ErrShareChecksum
+	}
+	return parseShareBytes(b)
+}
+
+func parseShareBytes(b []byte) (*SecretShare, error) {
+	if len(b) < secretShareHeaderLen || b[0] != secretShareVersion {
+		return nil, ErrShareFormat
+	}
+	s := &SecretShare{
+		ID:        binary.BigEndian.Uint16(b[1:3]),
+		Threshold: b[3],
+		Index:     b[4],
+		Kind:      SecretKind(b[5]),
+		secretLen: b[6],
+		Data:      b[secretShareHeaderLen:],
+	}
+	//share data holds the secret and its 4 byte checksum
+	if len(s.Data) != int(s.secretLen)+4 || s.Index == 0 || s.Threshold < 2 || s.Kind > SecretPrivateKey {
+		return nil, ErrShareFormat
+	}
+	return s, nil
+}
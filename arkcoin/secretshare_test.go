@@ -0,0 +1,94 @@
+package arkcoin
+
+import (
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestSplitPassphrase(t *testing.T) {
+	passphrase := "this is a top secret passphrase"
+	shares, err := SplitPassphrase(passphrase, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//shares go through both encodings
+	parsed := make([]*SecretShare, 0, 3)
+	for i, s := range shares[1:4] {
+		encoded := s.Base58()
+		if i%2 == 0 {
+			encoded = s.Words()
+		}
+		log.Println(t.Name(), s, encoded)
+		p, err := ParseSecretShare(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, p)
+	}
+
+	combined, err := CombinePassphrase(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combined != passphrase {
+		t.Error("wrong passphrase", combined)
+	}
+
+	key, err := CombinePrivateKey(parsed, ArkCoinMain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.WIFAddress() != NewPrivateKeyFromPassword(passphrase, ArkCoinMain).WIFAddress() {
+		t.Error("wrong private key")
+	}
+
+	if _, err = CombinePassphrase(parsed[:2]); err != ErrShareThreshold {
+		t.Error("expected ErrShareThreshold, got", err)
+	}
+}
+
+func TestSplitPrivateKey(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinDevTest)
+	shares, err := SplitPrivateKey(key, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	combined, err := CombinePrivateKey([]*SecretShare{shares[2], shares[0]}, ArkCoinDevTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combined.PublicKey.Address() != key.PublicKey.Address() {
+		t.Error("wrong private key")
+	}
+	if _, err = CombinePassphrase(shares); err != ErrShareKind {
+		t.Error("expected ErrShareKind, got", err)
+	}
+
+	other, _ := SplitPrivateKey(key, 3, 2)
+	if _, err = CombinePrivateKey([]*SecretShare{shares[0], other[1]}, ArkCoinDevTest); err != ErrShareMismatch {
+		t.Error("expected ErrShareMismatch, got", err)
+	}
+}
+
+func TestParseSecretShareErrors(t *testing.T) {
+	shares, _ := SplitPassphrase("passphrase", 3, 2)
+	words := shares[0].Words()
+
+	//swap the second word, it holds the share id
+	fields := strings.Fields(words)
+	if fields[1] == "zoo" {
+		fields[1] = "abandon"
+	} else {
+		fields[1] = "zoo"
+	}
+	tampered := strings.Join(fields, " ")
+	if _, err := ParseSecretShare(tampered); err != ErrShareChecksum {
+		t.Error("expected ErrShareChecksum, got", err)
+	}
+
+	if _, err := ParseSecretShare(words + " abandon"); err != ErrShareFormat {
+		t.Error("expected ErrShareFormat, got", err)
+	}
+}
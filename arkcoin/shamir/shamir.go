@@ -0,0 +1,149 @@
+//Package shamir implements Shamir's secret sharing over GF(256).
+//Every byte of the secret is shared with its own random polynomial of degree threshold-1.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+)
+
+var (
+	//ErrInvalidThreshold is returned when threshold is less than 2 or bigger than number of parts
+	ErrInvalidThreshold = errors.New("threshold must be at least 2 and not more than parts")
+	//ErrInvalidParts is returned when number of parts is more than 255
+	ErrInvalidParts = errors.New("number of parts must be between 2 and 255")
+	//ErrEmptySecret is returned when there is nothing to split
+	ErrEmptySecret = errors.New("secret is empty")
+	//ErrInvalidShares is returned when shares have different length, duplicate or zero x coordinates
+	ErrInvalidShares = errors.New("shares are inconsistent")
+)
+
+var (
+	expTable [510]byte
+	logTable [256]byte
+)
+
+func init() {
+	//generator 3 over AES polynomial x^8 + x^4 + x^3 + x + 1
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		expTable[i+255] = x
+		logTable[x] = byte(i)
+		x ^= mulNoTable(x, 2)
+	}
+}
+
+func mulNoTable(a, b byte) byte {
+	var p byte
+	for b != 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
+
+//Split splits secret into parts shares, any threshold of them can rebuild it with Combine.
+//Each share is len(secret)+1 bytes long, the last byte is its x coordinate (1..parts).
+func Split(secret []byte, parts, threshold int) ([][]byte, error) {
+	if parts < 2 || parts > 255 {
+		return nil, ErrInvalidParts
+	}
+	if threshold < 2 || threshold > parts {
+		return nil, ErrInvalidThreshold
+	}
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	shares := make([][]byte, parts)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+	for ix, b := range secret {
+		coefficients[0] = b
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			share[ix] = evaluate(coefficients, share[len(secret)])
+		}
+	}
+	return shares, nil
+}
+
+//Combine rebuilds the secret from shares created by Split. At least threshold shares must be
+//given, otherwise the result is random data - use a checksum on top to detect it.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrInvalidThreshold
+	}
+	length := len(shares[0])
+	if length < 2 {
+		return nil, ErrInvalidShares
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool)
+	for i, share := range shares {
+		if len(share) != length {
+			return nil, ErrInvalidShares
+		}
+		x := share[length-1]
+		if x == 0 || seen[x] {
+			return nil, ErrInvalidShares
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, length-1)
+	for ix := range secret {
+		//lagrange interpolation at x=0
+		var value byte
+		for i, xi := range xs {
+			basis := byte(1)
+			for j, xj := range xs {
+				if i != j {
+					basis = mul(basis, div(xj, xj^xi))
+				}
+			}
+			value ^= mul(shares[i][ix], basis)
+		}
+		secret[ix] = value
+	}
+	return secret, nil
+}
+
+//evaluate returns polynomial value at x using Horner's method
+func evaluate(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = mul(result, x) ^ coefficients[i]
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("this is a top secret passphrase")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatal("wrong number of shares", len(shares))
+	}
+
+	//every combination of 3 shares rebuilds the secret
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				combined, err := Combine([][]byte{shares[i], shares[j], shares[k]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(combined, secret) {
+					t.Error("wrong secret from shares", i, j, k)
+				}
+			}
+		}
+	}
+
+	combined, _ := Combine(shares[:2])
+	if bytes.Equal(combined, secret) {
+		t.Error("secret rebuilt from less than threshold shares")
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	if _, err := Split([]byte("secret"), 2, 3); err != ErrInvalidThreshold {
+		t.Error("expected ErrInvalidThreshold, got", err)
+	}
+	if _, err := Split([]byte("secret"), 256, 3); err != ErrInvalidParts {
+		t.Error("expected ErrInvalidParts, got", err)
+	}
+	if _, err := Split(nil, 5, 3); err != ErrEmptySecret {
+		t.Error("expected ErrEmptySecret, got", err)
+	}
+
+	shares, _ := Split([]byte("secret"), 3, 2)
+	if _, err := Combine([][]byte{shares[0], shares[0]}); err != ErrInvalidShares {
+		t.Error("expected ErrInvalidShares, got", err)
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			p := mul(byte(a), byte(b))
+			if p != mulNoTable(byte(a), byte(b)) {
+				t.Fatal("wrong table multiplication", a, b)
+			}
+			if div(p, byte(b)) != byte(a) {
+				t.Fatal("wrong division", a, b)
+			}
+		}
+	}
+}
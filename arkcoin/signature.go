@@ -0,0 +1,103 @@
+package arkcoin
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+var (
+	//ErrSignatureFormat is returned when signature is not a DER encoded sequence of two positive integers
+	ErrSignatureFormat = errors.New("signature is not valid DER")
+	//ErrSignatureNonMinimal is returned when DER integer has unnecessary leading zero bytes
+	ErrSignatureNonMinimal = errors.New("signature DER encoding is not minimal")
+	//ErrSignatureTrailingBytes is returned when there are bytes after the DER sequence
+	ErrSignatureTrailingBytes = errors.New("signature has trailing bytes after DER sequence")
+	//ErrSignatureHighS is returned when S is bigger than half of the curve order (malleable signature)
+	ErrSignatureHighS = errors.New("signature S value is not canonical low-S")
+	//ErrSignatureInvalid is returned when signature does not match data and public key
+	ErrSignatureInvalid = errors.New("signature is invalid")
+)
+
+var halfOrder = new(big.Int).Rsh(secp256k1.N, 1)
+
+//normalizeLowS replaces S with N-S when S is in the upper half of the curve order
+func normalizeLowS(sig *btcec.Signature) {
+	if sig.S.Cmp(halfOrder) > 0 {
+		sig.S = new(big.Int).Sub(secp256k1.N, sig.S)
+	}
+}
+
+//ParseStrictSignature parses DER signature following BIP66 rules and requires low-S.
+//Returned errors are ErrSignatureFormat, ErrSignatureNonMinimal, ErrSignatureTrailingBytes and ErrSignatureHighS.
+func ParseStrictSignature(sig []byte) (*btcec.Signature, error) {
+	//0x30 len 0x02 rlen r 0x02 slen s
+	if len(sig) < 8 || sig[0] != 0x30 {
+		return nil, ErrSignatureFormat
+	}
+	seqLen := int(sig[1])
+	if seqLen+2 > len(sig) {
+		return nil, ErrSignatureFormat
+	}
+
+	r, rest, err := parseDERInteger(sig[2 : seqLen+2])
+	if err != nil {
+		return nil, err
+	}
+	s, rest, err := parseDERInteger(rest)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, ErrSignatureFormat
+	}
+	if len(sig) != seqLen+2 {
+		return nil, ErrSignatureTrailingBytes
+	}
+
+	if r.Cmp(secp256k1.N) >= 0 || s.Cmp(secp256k1.N) >= 0 {
+		return nil, ErrSignatureFormat
+	}
+	if s.Cmp(halfOrder) > 0 {
+		return nil, ErrSignatureHighS
+	}
+	return &btcec.Signature{R: r, S: s}, nil
+}
+
+//parseDERInteger parses positive DER integer and returns it with the remaining bytes
+func parseDERInteger(b []byte) (*big.Int, []byte, error) {
+	if len(b) < 3 || b[0] != 0x02 {
+		return nil, nil, ErrSignatureFormat
+	}
+	length := int(b[1])
+	if length == 0 || length+2 > len(b) {
+		return nil, nil, ErrSignatureFormat
+	}
+	value := b[2 : length+2]
+	if value[0]&0x80 != 0 {
+		return nil, nil, ErrSignatureFormat
+	}
+	if length > 1 && value[0] == 0x00 && value[1]&0x80 == 0 {
+		return nil, nil, ErrSignatureNonMinimal
+	}
+	n := new(big.Int).SetBytes(value)
+	if n.Sign() == 0 {
+		return nil, nil, ErrSignatureFormat
+	}
+	return n, b[length+2:], nil
+}
+
+//VerifyStrict verifies signature like Verify, but rejects malleable signatures -
+//high-S values, non-minimal DER encoding and trailing bytes (see ParseStrictSignature).
+//Use it for signatures received from other peers.
+func (pub *PublicKey) VerifyStrict(signature []byte, data []byte) error {
+	sig, err := ParseStrictSignature(signature)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(data, pub.PublicKey) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
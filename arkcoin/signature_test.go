@@ -0,0 +1,76 @@
+package arkcoin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+//derSignature encodes r and s as DER without any normalization
+func derSignature(r, s []byte) []byte {
+	b := []byte{0x30, byte(4 + len(r) + len(s)), 0x02, byte(len(r))}
+	b = append(b, r...)
+	b = append(b, 0x02, byte(len(s)))
+	return append(b, s...)
+}
+
+func derInt(n *big.Int) []byte {
+	b := n.Bytes()
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0x00}, b...)
+	}
+	return b
+}
+
+func TestSignLowS(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	hash := make([]byte, 32)
+	for i := 0; i < 64; i++ {
+		hash[0] = byte(i)
+		sig, err := key.Sign(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = key.PublicKey.VerifyStrict(sig, hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerifyStrict(t *testing.T) {
+	key := NewPrivateKeyFromPassword("this is a top secret passphrase", ArkCoinMain)
+	hash := make([]byte, 32)
+	der, _ := key.Sign(hash)
+	sig, err := btcec.ParseSignature(der, secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	highS := derSignature(derInt(sig.R), derInt(new(big.Int).Sub(secp256k1.N, sig.S)))
+	if err = key.PublicKey.Verify(highS, hash); err != nil {
+		t.Error("lenient verify rejected high-S signature", err)
+	}
+	if err = key.PublicKey.VerifyStrict(highS, hash); err != ErrSignatureHighS {
+		t.Error("expected ErrSignatureHighS, got", err)
+	}
+
+	nonMinimal := derSignature(append([]byte{0x00, 0x00}, derInt(sig.R)...), derInt(sig.S))
+	if err = key.PublicKey.VerifyStrict(nonMinimal, hash); err != ErrSignatureNonMinimal {
+		t.Error("expected ErrSignatureNonMinimal, got", err)
+	}
+
+	trailing := append(append([]byte{}, der...), 0x01)
+	if err = key.PublicKey.VerifyStrict(trailing, hash); err != ErrSignatureTrailingBytes {
+		t.Error("expected ErrSignatureTrailingBytes, got", err)
+	}
+
+	if err = key.PublicKey.VerifyStrict(der[:len(der)-1], hash); err != ErrSignatureFormat {
+		t.Error("expected ErrSignatureFormat, got", err)
+	}
+
+	hash[0] = 1
+	if err = key.PublicKey.VerifyStrict(der, hash); err != ErrSignatureInvalid {
+		t.Error("expected ErrSignatureInvalid, got", err)
+	}
+}
@@ -0,0 +1,302 @@
+package arkcoin
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	//ErrVanityPattern is returned when no prefix, suffix or regexp is given, or they contain non base58 characters
+	ErrVanityPattern = errors.New("vanity pattern is empty or contains characters outside of base58 alphabet")
+	//ErrVanityImpossible is returned when prefix first character can never appear on the network
+	ErrVanityImpossible = errors.New("vanity prefix can not match addresses of this network")
+)
+
+//VanityOptions describe what address to search for and how.
+//All given conditions (Prefix, Suffix and Regexp) must match.
+type VanityOptions struct {
+	Prefix          string
+	Suffix          string
+	Regexp          string
+	CaseInsensitive bool
+	//Passphrase search generates 12 word BIP39 passphrases instead of random keys,
+	//so the result can be used as a normal account passphrase
+	Passphrase bool
+	//Workers is the number of searching goroutines, runtime.NumCPU() if 0
+	Workers int
+	//Progress is called every ProgressInterval (1 second if 0) while searching
+	Progress         func(VanityProgress)
+	ProgressInterval time.Duration
+}
+
+//VanityProgress is the search progress report
+type VanityProgress struct {
+	Attempts uint64
+	Elapsed  time.Duration
+	Rate     float64 //attempts per second
+	//Difficulty is the expected number of attempts, 0 if unknown (regexp search)
+	Difficulty float64
+	//Expected is the expected time to find a match at current rate (50% probability), 0 if unknown
+	Expected time.Duration
+}
+
+//VanityResult holds the found key, passphrase in Passphrase mode and the address
+type VanityResult struct {
+	Key        *PrivateKey
+	Passphrase string
+	Address    string
+	Attempts   uint64
+	Elapsed    time.Duration
+}
+
+type vanityMatcher struct {
+	prefix, suffix string
+	re             *regexp.Regexp
+	lower          bool
+}
+
+func (m *vanityMatcher) match(address string) bool {
+	if m.lower {
+		address = strings.ToLower(address)
+	}
+	return strings.HasPrefix(address, m.prefix) && strings.HasSuffix(address, m.suffix) && (m.re == nil || m.re.MatchString(address))
+}
+
+//VanityDifficulty returns the expected number of attempts to find address of network param with prefix and suffix
+func VanityDifficulty(param *Params, prefix, suffix string, caseInsensitive bool) float64 {
+	difficulty := 1.0
+	//first address character is given by the network version byte, some version bytes allow two characters
+	if prefix != "" {
+		first, size := utf8.DecodeRuneInString(prefix)
+		difficulty /= firstCharacterShare(param.AddressHeader, first, caseInsensitive)
+		prefix = prefix[size:]
+	}
+	for _, c := range prefix + suffix {
+		difficulty *= 58 / float64(alphabetMatches(c, caseInsensitive))
+	}
+	return difficulty
+}
+
+//firstCharacterShare returns the share of addresses with version byte starting with character c.
+//Addresses encode 25 bytes, so they are the numbers from version<<192 to ((version+1)<<192)-1.
+func firstCharacterShare(version byte, c rune, caseInsensitive bool) float64 {
+	if version == 0 {
+		//leading zero byte is encoded as 1
+		if c == '1' {
+			return 1
+		}
+		return 0
+	}
+	lo := new(big.Int).Lsh(big.NewInt(int64(version)), 192)
+	hi := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(int64(version)+1), 192), big.NewInt(1))
+
+	count := new(big.Int)
+	for d, a := range base58Alphabet {
+		if d == 0 || a != c && !(caseInsensitive && strings.EqualFold(string(a), string(c))) {
+			continue
+		}
+		for _, n := range []*big.Int{lo, hi} {
+			//numbers of the same length starting with digit d
+			unit := new(big.Int).Exp(big.NewInt(58), big.NewInt(int64(base58Length(n)-1)), nil)
+			from := new(big.Int).Mul(unit, big.NewInt(int64(d)))
+			to := new(big.Int).Sub(new(big.Int).Add(from, unit), big.NewInt(1))
+			if from.Cmp(lo) < 0 {
+				from.Set(lo)
+			}
+			if to.Cmp(hi) > 0 {
+				to.Set(hi)
+			}
+			if to.Cmp(from) >= 0 {
+				count.Add(count, to.Sub(to, from).Add(to, big.NewInt(1)))
+			}
+			if base58Length(lo) == base58Length(hi) {
+				break
+			}
+		}
+	}
+	share, _ := new(big.Rat).SetFrac(count, new(big.Int).Lsh(big.NewInt(1), 192)).Float64()
+	return share
+}
+
+//base58Length returns the number of base58 digits of positive n
+func base58Length(n *big.Int) int {
+	length := 0
+	for rest := new(big.Int).Set(n); rest.Sign() > 0; length++ {
+		rest.Div(rest, big.NewInt(58))
+	}
+	return length
+}
+
+func alphabetMatches(c rune, caseInsensitive bool) int {
+	if !caseInsensitive {
+		if strings.ContainsRune(base58Alphabet, c) {
+			return 1
+		}
+		return 0
+	}
+	n := 0
+	for _, a := range base58Alphabet {
+		if strings.EqualFold(string(a), string(c)) {
+			n++
+		}
+	}
+	return n
+}
+
+func newVanityMatcher(param *Params, opts VanityOptions) (*vanityMatcher, error) {
+	if opts.Prefix == "" && opts.Suffix == "" && opts.Regexp == "" {
+		return nil, ErrVanityPattern
+	}
+	for _, c := range opts.Prefix + opts.Suffix {
+		if alphabetMatches(c, opts.CaseInsensitive) == 0 {
+			return nil, ErrVanityPattern
+		}
+	}
+
+	m := &vanityMatcher{prefix: opts.Prefix, suffix: opts.Suffix, lower: opts.CaseInsensitive}
+	if opts.Regexp != "" {
+		expr := opts.Regexp
+		if opts.CaseInsensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		m.re = re
+	}
+	if m.lower {
+		m.prefix = strings.ToLower(m.prefix)
+		m.suffix = strings.ToLower(m.suffix)
+	}
+
+	if m.prefix != "" && firstCharacterShare(param.AddressHeader, rune(m.prefix[0]), m.lower) == 0 {
+		return nil, ErrVanityImpossible
+	}
+	return m, nil
+}
+
+//VanitySearch searches for key with address matching opts on all CPU cores, until found or ctx is done.
+//ctx.Err() is returned when search is cancelled.
+func VanitySearch(ctx context.Context, param *Params, opts VanityOptions) (*VanityResult, error) {
+	m, err := newVanityMatcher(param, opts)
+	if err != nil {
+		return nil, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	difficulty := 0.0
+	if opts.Regexp == "" {
+		difficulty = VanityDifficulty(param, opts.Prefix, opts.Suffix, opts.CaseInsensitive)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var attempts uint64
+	start := time.Now()
+	found := make(chan *VanityResult, workers)
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := vanityWorker(ctx, param, m, opts.Passphrase, &attempts)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if result != nil {
+				found <- result
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case result := <-found:
+			result.Attempts = atomic.LoadUint64(&attempts)
+			result.Elapsed = time.Since(start)
+			return result, nil
+		case err := <-errs:
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			if opts.Progress != nil {
+				opts.Progress(vanityProgress(atomic.LoadUint64(&attempts), time.Since(start), difficulty))
+			}
+		}
+	}
+}
+
+func vanityProgress(attempts uint64, elapsed time.Duration, difficulty float64) VanityProgress {
+	p := VanityProgress{Attempts: attempts, Elapsed: elapsed, Difficulty: difficulty}
+	if elapsed > 0 {
+		p.Rate = float64(attempts) / elapsed.Seconds()
+	}
+	if p.Rate > 0 && difficulty > 0 {
+		//attempts needed for 50% probability of success
+		expectedAttempts := math.Log(0.5) / math.Log1p(-1/difficulty)
+		p.Expected = time.Duration(math.MaxInt64)
+		if expected := expectedAttempts / p.Rate * float64(time.Second); expected < math.MaxInt64 {
+			p.Expected = time.Duration(expected)
+		}
+	}
+	return p
+}
+
+func vanityWorker(ctx context.Context, param *Params, m *vanityMatcher, passphraseMode bool, attempts *uint64) (*VanityResult, error) {
+	for i := 0; ; i++ {
+		if i%64 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, nil
+			default:
+			}
+		}
+
+		var key *PrivateKey
+		var passphrase string
+		var err error
+		if passphraseMode {
+			if passphrase, err = NewMnemonic(12); err != nil {
+				return nil, err
+			}
+			key = NewPrivateKeyFromPassword(passphrase, param)
+		} else if key, err = Generate(param); err != nil {
+			return nil, err
+		}
+
+		atomic.AddUint64(attempts, 1)
+		address := key.PublicKey.Address()
+		if m.match(address) {
+			return &VanityResult{Key: key, Passphrase: passphrase, Address: address}, nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package arkcoin
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVanitySearch(t *testing.T) {
+	result, err := VanitySearch(context.Background(), ArkCoinDevTest, VanityOptions{Prefix: "dA", CaseInsensitive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), result.Address, result.Attempts, result.Elapsed)
+	if !strings.HasPrefix(strings.ToLower(result.Address), "da") || result.Key.PublicKey.Address() != result.Address {
+		t.Error("wrong vanity address", result.Address)
+	}
+
+	result, err = VanitySearch(context.Background(), ArkCoinMain, VanityOptions{Suffix: "k", Regexp: "[0-9]", Passphrase: true, Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Println(t.Name(), result.Address, result.Passphrase)
+	if !strings.HasSuffix(result.Address, "k") || NewPrivateKeyFromPassword(result.Passphrase, ArkCoinMain).PublicKey.Address() != result.Address {
+		t.Error("wrong passphrase vanity address", result.Address)
+	}
+	if err = ValidateMnemonic(result.Passphrase); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestVanitySearchCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	var progress []VanityProgress
+	_, err := VanitySearch(ctx, ArkCoinMain, VanityOptions{
+		Prefix:           "Ark9oLang",
+		ProgressInterval: 100 * time.Millisecond,
+		Progress: func(p VanityProgress) {
+			progress = append(progress, p)
+		},
+	})
+	if err != context.DeadlineExceeded {
+		t.Error("expected context.DeadlineExceeded, got", err)
+	}
+	if len(progress) == 0 || progress[len(progress)-1].Attempts == 0 || progress[0].Expected <= 0 {
+		t.Error("no progress reported", progress)
+	}
+}
+
+func TestVanityPatternErrors(t *testing.T) {
+	if _, err := VanitySearch(context.Background(), ArkCoinMain, VanityOptions{Prefix: "A0"}); err != ErrVanityPattern {
+		t.Error("expected ErrVanityPattern, got", err)
+	}
+	if _, err := VanitySearch(context.Background(), ArkCoinMain, VanityOptions{}); err != ErrVanityPattern {
+		t.Error("expected ErrVanityPattern, got", err)
+	}
+	if _, err := VanitySearch(context.Background(), ArkCoinMain, VanityOptions{Prefix: "D"}); err != ErrVanityImpossible {
+		t.Error("expected ErrVanityImpossible, got", err)
+	}
+	if d := VanityDifficulty(ArkCoinMain, "Aa", "", false); d != 58 {
+		t.Error("wrong difficulty", d)
+	}
+}
+
+func TestVanityTwoFirstCharacters(t *testing.T) {
+	//addresses of version byte 24 start with A or B
+	param := *ArkCoinMain
+	param.AddressHeader = 24
+	result, err := VanitySearch(context.Background(), &param, VanityOptions{Prefix: "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Address, "B") {
+		t.Error("wrong vanity address", result.Address)
+	}
+	if _, err = VanitySearch(context.Background(), &param, VanityOptions{Prefix: "C"}); err != ErrVanityImpossible {
+		t.Error("expected ErrVanityImpossible, got", err)
+	}
+	a, b := VanityDifficulty(&param, "A", "", false), VanityDifficulty(&param, "B", "", false)
+	if a <= 1 || b <= a || math.Abs(1/a+1/b-1) > 1e-9 {
+		t.Error("wrong difficulty", a, b)
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/core"
+)
+
+var reader = bufio.NewReader(os.Stdin)
+
+func usage() {
+	fmt.Println("Create unsigned transaction envelopes online, sign them offline and broadcast them online.")
+	fmt.Println("")
+	fmt.Println("Usage:")
+	fmt.Println("\tarkgoenvelope create -sender <public key> -in templates.json -out unsigned.json [-devnet]")
+	fmt.Println("\tarkgoenvelope sign -in unsigned.json -out signed.json [-wif] [-second] [-devnet]")
+	fmt.Println("\tarkgoenvelope merge -out merged.json signed1.json signed2.json ...")
+	fmt.Println("\tarkgoenvelope verify -in signed.json [-devnet]")
+	fmt.Println("\tarkgoenvelope broadcast -in signed.json [-devnet]")
+	fmt.Println("")
+	fmt.Println("sign works offline, secrets are read from standard input.")
+}
+
+func readLine(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func readEnvelope(fileName string) (*core.Envelope, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return core.ParseEnvelope(data)
+}
+
+func writeEnvelope(envelope *core.Envelope, fileName string) error {
+	data, err := envelope.ToJSON()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, 0600)
+}
+
+func printSummary(envelope *core.Envelope) {
+	color.HiYellow("Envelope created %s for network %s", envelope.Created.Local().Format("2006-01-02 15:04:05"), envelope.Nethash)
+	for _, line := range envelope.Summary {
+		fmt.Println(line)
+	}
+}
+
+//useNetwork loads network configuration from peers, it fails when no peer answered
+func useNetwork(devnet bool) (*core.ArkClient, error) {
+	arkclient := core.NewArkClient(nil)
+	if devnet {
+		return arkclient.SwitchNetwork(core.DEVNET)
+	}
+	if !core.EnvironmentParams.Success {
+		return arkclient.SwitchNetwork(core.MAINNET)
+	}
+	return arkclient, nil
+}
+
+//readSigner reads passphrase or WIF, empty input returns nil signer
+func readSigner(prompt string, wif bool) (core.Signer, error) {
+	if wif {
+		prompt += " (WIF)"
+	}
+	secret := readLine(prompt + "\n-->")
+	switch {
+	case secret == "":
+		return nil, nil
+	case wif:
+		return core.NewWIFSigner(secret)
+	}
+	return core.NewPassphraseSigner(secret), nil
+}
+
+func create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	sender := flags.String("sender", "", "hex public key of the sender")
+	in := flags.String("in", "templates.json", "JSON array of transaction templates")
+	out := flags.String("out", "unsigned.json", "unsigned envelope file")
+	devnet := flags.Bool("devnet", false, "use DEVNET")
+	flags.Parse(args)
+
+	if _, err := useNetwork(*devnet); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	var templates []core.Transaction
+	if err = json.Unmarshal(data, &templates); err != nil {
+		return err
+	}
+	envelope, err := core.NewEnvelope(*sender, templates)
+	if err != nil {
+		return err
+	}
+	printSummary(envelope)
+	return writeEnvelope(envelope, *out)
+}
+
+func sign(args []string) error {
+	flags := flag.NewFlagSet("sign", flag.ExitOnError)
+	in := flags.String("in", "unsigned.json", "unsigned envelope file")
+	out := flags.String("out", "signed.json", "signed envelope file")
+	wif := flags.Bool("wif", false, "read private keys in WIF format instead of passphrases")
+	second := flags.Bool("second", false, "add only second signatures to signed envelope")
+	devnet := flags.Bool("devnet", false, "use DEVNET addresses")
+	flags.Parse(args)
+
+	//no peers are contacted, the signing machine can stay offline
+	if *devnet {
+		arkcoin.SetActiveCoinConfiguration(arkcoin.ArkCoinDevTest)
+	} else {
+		arkcoin.SetActiveCoinConfiguration(arkcoin.ArkCoinMain)
+	}
+	envelope, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	printSummary(envelope)
+	if readLine("\nSign these transactions? [y/N]\n-->") != "y" {
+		return fmt.Errorf("signing cancelled")
+	}
+
+	if *second {
+		secondSigner, err := readSigner("Enter second account passphrase", *wif)
+		if err != nil || secondSigner == nil {
+			return fmt.Errorf("second secret is needed: %v", err)
+		}
+		if err = envelope.SecondSign(secondSigner); err != nil {
+			return err
+		}
+	} else {
+		signer, err := readSigner("Enter account passphrase", *wif)
+		if err != nil || signer == nil {
+			return fmt.Errorf("secret is needed: %v", err)
+		}
+		secondSigner, err := readSigner("Enter second account passphrase, empty if the account has none", *wif)
+		if err != nil {
+			return err
+		}
+		if err = envelope.Sign(signer, secondSigner); err != nil {
+			return err
+		}
+	}
+	color.HiGreen("Envelope signed")
+	return writeEnvelope(envelope, *out)
+}
+
+func merge(args []string) error {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	out := flags.String("out", "merged.json", "merged envelope file")
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		return fmt.Errorf("at least two envelopes are needed")
+	}
+	envelope, err := readEnvelope(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, fileName := range flags.Args()[1:] {
+		other, err := readEnvelope(fileName)
+		if err != nil {
+			return fmt.Errorf("%s: %s", fileName, err.Error())
+		}
+		if err = envelope.Merge(other); err != nil {
+			return fmt.Errorf("%s: %s", fileName, err.Error())
+		}
+	}
+	printSummary(envelope)
+	return writeEnvelope(envelope, *out)
+}
+
+func verify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := flags.String("in", "signed.json", "signed envelope file")
+	devnet := flags.Bool("devnet", false, "use DEVNET")
+	flags.Parse(args)
+
+	if _, err := useNetwork(*devnet); err != nil {
+		return err
+	}
+	envelope, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	printSummary(envelope)
+	if err = envelope.Verify(); err != nil {
+		return err
+	}
+	color.HiGreen("Envelope is valid")
+	return nil
+}
+
+func broadcast(args []string) error {
+	flags := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	in := flags.String("in", "signed.json", "signed envelope file")
+	devnet := flags.Bool("devnet", false, "use DEVNET")
+	flags.Parse(args)
+
+	arkclient, err := useNetwork(*devnet)
+	if err != nil {
+		return err
+	}
+	envelope, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	printSummary(envelope)
+	ids, err := postEnvelope(arkclient, envelope)
+	if err != nil {
+		return err
+	}
+	color.HiGreen("Transactions sent:")
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+//postEnvelope verifies envelope and posts its transactions, ids of accepted transactions are returned
+func postEnvelope(arkclient *core.ArkClient, envelope *core.Envelope) ([]string, error) {
+	if err := envelope.Verify(); err != nil {
+		return nil, err
+	}
+	res, resp, err := arkclient.PostTransaction(envelope.Payload())
+	if resp == nil {
+		return nil, fmt.Errorf("peer not responding: %v", err)
+	}
+	if !res.Success {
+		return nil, fmt.Errorf("transactions not accepted: %s", strings.TrimSpace(res.Message+" "+res.Error))
+	}
+	return res.TransactionIDs, nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "create":
+		err = create(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "merge":
+		err = merge(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	case "broadcast":
+		err = broadcast(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		color.HiRed("Error: %s", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/core"
+)
+
+//testPeer returns client of test server answering posted transactions with response
+func testPeer(response string) (*core.ArkClient, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/peer/transactions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	address, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(address.Port())
+	return core.NewArkClientFromPeer(core.Peer{IP: address.Hostname(), Port: port}), server.Close
+}
+
+func signedEnvelope(t *testing.T) *core.Envelope {
+	arkcoin.SetActiveCoinConfiguration(arkcoin.ArkCoinMain)
+	if core.EnvironmentParams.Network.Nethash == "" {
+		core.EnvironmentParams.Network.Nethash = "6e84d08bd299ed97c212c886c98a57e36545c8f5d645ca7eeae63a8bd62d8988"
+	}
+	signer := core.NewPassphraseSigner("this is a top secret passphrase")
+	envelope, err := core.NewEnvelope(hex.EncodeToString(signer.PublicKey()), []core.Transaction{
+		{Type: core.SENDARK, RecipientID: "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25", Amount: 100000000, Fee: 10000000},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = envelope.Sign(signer, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	return envelope
+}
+
+func TestPostEnvelope(t *testing.T) {
+	envelope := signedEnvelope(t)
+
+	arkclient, closePeer := testPeer(`{"success":true,"transactionIds":["` + envelope.Transactions[0].ID + `"]}`)
+	ids, err := postEnvelope(arkclient, envelope)
+	closePeer()
+	if err != nil || len(ids) != 1 || ids[0] != envelope.Transactions[0].ID {
+		t.Error("Accepted envelope not posted", ids, err)
+	}
+
+	arkclient, closePeer = testPeer(`{"success":false,"error":"Invalid transaction"}`)
+	_, err = postEnvelope(arkclient, envelope)
+	closePeer()
+	if err == nil {
+		t.Error("Rejected envelope posted")
+	}
+	log.Println(t.Name(), err)
+
+	//peer is closed now
+	if _, err = postEnvelope(arkclient, envelope); err == nil {
+		t.Error("Envelope posted to closed peer")
+	}
+	log.Println(t.Name(), err)
+}
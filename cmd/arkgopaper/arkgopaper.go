@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/core"
+	log "github.com/sirupsen/logrus"
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>%s paper wallets</title>
+<style>
+@page { size: A4; margin: 15mm; }
+body { margin: 0; font-family: monospace; }
+.wallet { width: 180mm; margin: 0 auto 10mm; break-inside: avoid; page-break-inside: avoid; }
+.wallet svg { width: 100%%; height: auto; display: block; }
+</style></head><body>
+`
+
+func main() {
+	count := flag.Int("n", 1, "number of wallets to generate")
+	format := flag.String("format", "html", "output format: html (one printable document), svg or png (file per wallet)")
+	out := flag.String("out", "", "output file for html, output directory for svg and png (default paperwallets.html or paperwallets)")
+	devnet := flag.Bool("devnet", false, "generate DEVNET wallets")
+	flag.Parse()
+
+	if *count < 1 {
+		log.Fatal("Number of wallets must be at least 1")
+	}
+	//no peers are contacted, wallets can be generated on an offline machine
+	var network core.ArkNetworkType = core.MAINNET
+	params := arkcoin.ArkCoinMain
+	if *devnet {
+		network, params = core.DEVNET, arkcoin.ArkCoinDevTest
+	}
+	arkcoin.SetActiveCoinConfiguration(params)
+
+	wallets := make([]*wallet, *count)
+	for i := range wallets {
+		w, err := newWallet(network, params)
+		if err != nil {
+			log.Fatal("Unable to generate wallet: ", err.Error())
+		}
+		wallets[i] = w
+	}
+
+	var err error
+	switch *format {
+	case "html":
+		if *out == "" {
+			*out = "paperwallets.html"
+		}
+		err = writeHTML(*out, wallets)
+	case "svg", "png":
+		if *out == "" {
+			*out = "paperwallets"
+		}
+		err = writeFiles(*out, *format, wallets)
+	default:
+		log.Fatal("Unknown format ", *format)
+	}
+	if err != nil {
+		log.Fatal("Unable to write paper wallets: ", err.Error())
+	}
+
+	color.HiGreen("Generated %d %s paper wallet(s) in %s", len(wallets), networkName(network), *out)
+	for i, w := range wallets {
+		fmt.Printf("%3d. %s\n", i+1, w.Address)
+	}
+	color.HiYellow("Output contains private keys - keep it safe and delete the files after printing.")
+}
+
+//writeHTML writes all wallets into one printable document, cards are not split across pages
+func writeHTML(path string, wallets []*wallet) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, htmlHeader, html.EscapeString(wallets[0].Network))
+	for _, w := range wallets {
+		b.WriteString(`<div class="wallet">`)
+		b.WriteString(w.SVG())
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return ioutil.WriteFile(path, []byte(b.String()), 0600)
+}
+
+//writeFiles writes every wallet into its own svg or png file in dir
+func writeFiles(dir, format string, wallets []*wallet) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for i, w := range wallets {
+		path := filepath.Join(dir, fmt.Sprintf("wallet-%03d.%s", i+1, format))
+		if format == "svg" {
+			if err := ioutil.WriteFile(path, []byte(w.SVG()), 0600); err != nil {
+				return err
+			}
+			continue
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, w.Image())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
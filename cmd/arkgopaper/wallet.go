@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/arkcoin/qrcode"
+	"github.com/kristjank/ark-go/core"
+)
+
+//card layout, in pixels for PNG and user units for SVG
+const (
+	cardWidth  = 720
+	cardHeight = 360
+	cardMargin = 20
+	qrSide     = 180
+	qrTop      = 70
+)
+
+type wallet struct {
+	Network   string
+	Address   string
+	WIF       string
+	addressQR *qrcode.Code
+	wifQR     *qrcode.Code
+}
+
+//networkName returns token and network type, for example "ARK MAINNET"
+func networkName(network core.ArkNetworkType) string {
+	token := core.EnvironmentParams.Network.Token
+	if token == "" {
+		token = "ARK"
+	}
+	return token + " " + network.String()
+}
+
+//newWallet generates new key with address and WIF of params
+func newWallet(network core.ArkNetworkType, params *arkcoin.Params) (*wallet, error) {
+	key, err := arkcoin.Generate(params)
+	if err != nil {
+		return nil, err
+	}
+	w := &wallet{Network: networkName(network), Address: key.PublicKey.Address(), WIF: key.WIFAddress()}
+	if w.addressQR, err = qrcode.EncodeString(w.Address, qrcode.Medium); err != nil {
+		return nil, err
+	}
+	if w.wifQR, err = qrcode.EncodeString(w.WIF, qrcode.Medium); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+//qrSVG returns SVG group drawing code with quiet zone in qrSide square at x, y
+func qrSVG(code *qrcode.Code, x int) string {
+	scale := float64(qrSide) / float64(code.Size+2*qrcode.QuietZone)
+	return fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#fff" stroke="#999"/>`+
+		`<path transform="translate(%d %d) scale(%.4f) translate(%d %d)" fill="#000" d="%s"/>`,
+		x, qrTop, qrSide, qrSide, x, qrTop, scale, qrcode.QuietZone, qrcode.QuietZone, code.Path())
+}
+
+//SVG returns self-contained SVG card with address and WIF as QR codes and text
+func (w *wallet) SVG() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" shape-rendering="crispEdges">`,
+		cardWidth, cardHeight, cardWidth, cardHeight)
+	fmt.Fprintf(&b, `<rect x="1" y="1" width="%d" height="%d" fill="#fff" stroke="#000" stroke-dasharray="6 4"/>`, cardWidth-2, cardHeight-2)
+	fmt.Fprintf(&b, `<text x="%d" y="45" font-size="26" font-weight="bold">%s PAPER WALLET</text>`, cardMargin, html.EscapeString(w.Network))
+
+	b.WriteString(qrSVG(w.addressQR, cardMargin))
+	b.WriteString(qrSVG(w.wifQR, cardWidth-cardMargin-qrSide))
+	fmt.Fprintf(&b, `<text x="%d" y="272" font-size="14" font-weight="bold">ADDRESS (share to receive)</text>`, cardMargin)
+	fmt.Fprintf(&b, `<text x="%d" y="272" font-size="14" font-weight="bold" text-anchor="end">PRIVATE KEY (keep secret)</text>`, cardWidth-cardMargin)
+	fmt.Fprintf(&b, `<text x="%d" y="305" font-size="14">Address: %s</text>`, cardMargin, w.Address)
+	fmt.Fprintf(&b, `<text x="%d" y="330" font-size="14">WIF: %s</text>`, cardMargin, w.WIF)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+//Image returns PNG ready card with address and WIF as QR codes and text
+func (w *wallet) Image() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, cardWidth, cardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: 0xff}), image.ZP, draw.Src)
+	for x := 0; x < cardWidth; x++ {
+		if x%10 < 6 {
+			img.SetGray(x, 0, color.Gray{})
+			img.SetGray(x, cardHeight-1, color.Gray{})
+		}
+	}
+	for y := 0; y < cardHeight; y++ {
+		if y%10 < 6 {
+			img.SetGray(0, y, color.Gray{})
+			img.SetGray(cardWidth-1, y, color.Gray{})
+		}
+	}
+
+	drawText(img, cardMargin, 22, 3, w.Network+" PAPER WALLET")
+	drawQR(img, w.addressQR, cardMargin)
+	drawQR(img, w.wifQR, cardWidth-cardMargin-qrSide)
+	drawText(img, cardMargin, 262, 2, "ADDRESS (share)")
+	label := "PRIVATE KEY (secret)"
+	drawText(img, cardWidth-cardMargin-textWidth(label, 2), 262, 2, label)
+	drawText(img, cardMargin, 295, 2, "Address: "+w.Address)
+	drawText(img, cardMargin, 320, 2, "WIF: "+w.WIF)
+	return img
+}
+
+//drawQR draws code centered in qrSide square at x, with the largest whole module size that fits
+func drawQR(img *image.Gray, code *qrcode.Code, x int) {
+	moduleSize := qrSide / (code.Size + 2*qrcode.QuietZone)
+	qr := code.Image(moduleSize)
+	offset := (qrSide - qr.Bounds().Dx()) / 2
+	draw.Draw(img, qr.Bounds().Add(image.Pt(x+offset, qrTop+offset)), qr, image.ZP, draw.Src)
+}
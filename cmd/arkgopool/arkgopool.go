@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
@@ -77,14 +78,18 @@ func broadCastServiceMode(status bool) {
 }
 
 func readAccountData() (string, string) {
-	fmt.Println("\nEnter account passphrase")
+	fmt.Println("\nEnter account passphrase or private key (WIF)")
 	fmt.Print("-->")
 	pass1, _ := reader.ReadString('\n')
 	re := regexp.MustCompile("\r?\n")
 	pass1 = re.ReplaceAllString(pass1, "")
 
+	if !checkSecret(pass1) {
+		return "error", ""
+	}
+
 	pass2 := ""
-	key := arkcoin.NewPrivateKeyFromPassword(pass1, arkcoin.ActiveCoinConfig)
+	key := accountKey(pass1)
 
 	accountResp, _, _ := arkclient.GetAccount(core.AccountQueryParams{Address: key.PublicKey.Address()})
 	deleResp, _, _ := arkclient.GetDelegate(core.DelegateQueryParams{PublicKey: string(key.PublicKey.Serialize())})
@@ -94,16 +99,69 @@ func readAccountData() (string, string) {
 	}
 
 	if accountResp.Account.SecondSignature == 1 {
-		fmt.Println("\nEnter second account passphrase for delegate: " + deleResp.SingleDelegate.Username + "[" + key.PublicKey.Address() + "]")
+		fmt.Println("\nEnter second account passphrase or private key (WIF) for delegate: " + deleResp.SingleDelegate.Username + "[" + key.PublicKey.Address() + "]")
 		fmt.Print("-->")
 		pass2, _ = reader.ReadString('\n')
 		re := regexp.MustCompile("\r?\n")
 		pass2 = re.ReplaceAllString(pass2, "")
+
+		if !checkSecret(pass2) {
+			return "error", ""
+		}
+		if err := checkSecondKey(pass2, accountResp.Account); err != nil {
+			log.Error(err.Error())
+			color.HiRed("%s", err.Error())
+			return "error", ""
+		}
 	}
 
 	return pass1, pass2
 }
 
+//checkSecret reports entered passphrase or WIF, false is returned for secrets which are not Ark keys
+func checkSecret(secret string) bool {
+	if isUncompressedWIF(secret) {
+		log.Error("Entered private key is in uncompressed WIF format")
+		color.HiRed("Entered private key is in uncompressed WIF format, Ark keys are compressed")
+		return false
+	} else if isWIF(secret) {
+		log.Info("Using entered private key (WIF)")
+	} else if err := arkcoin.ValidateMnemonic(secret); err != nil {
+		//error quotes words of the passphrase, it is shown on the console only
+		log.Warn("Entered passphrase is not a valid BIP39 mnemonic")
+		color.Set(color.FgHiRed)
+		fmt.Println("WARNING: entered passphrase is not a valid BIP39 mnemonic:", err.Error())
+		color.Unset()
+	}
+	return true
+}
+
+//verifySignedMessage reads signed message JSON (as exported by the Ark wallet)
+//and checks that the voter owns the address
+func verifySignedMessage() {
+	fmt.Println("\nEnter signed message JSON")
+	fmt.Print("-->")
+	jsonMsg, _ := reader.ReadString('\n')
+	msg, err := arkcoin.ParseSignedMessage(jsonMsg)
+	if err != nil {
+		color.HiRed("Unable to parse signed message: %s", err.Error())
+		return
+	}
+
+	fmt.Println("\nEnter voter address")
+	fmt.Print("-->")
+	address, _ := reader.ReadString('\n')
+	address = strings.TrimSpace(address)
+
+	if err = msg.VerifyAddress(address, arkcoin.ActiveCoinConfig); err != nil {
+		log.Warn("Signed message verification failed for ", address, ": ", err.Error())
+		color.HiRed("Signed message verification FAILED: %s", err.Error())
+		return
+	}
+	log.Info("Signed message verified for ", address, ": ", msg.Message)
+	color.HiGreen("Signed message verified. Address %s signed: %s", address, msg.Message)
+}
+
 func loadConfig() {
 	viper.SetConfigName("config")   // name of config file (without extension)
 	viper.AddConfigPath("settings") // path to look for the config file in
@@ -134,6 +192,7 @@ func loadConfig() {
 	viper.SetDefault("voters.minamount", 0.0)
 	viper.SetDefault("voters.minVoteTime", 0)
 	viper.SetDefault("voters.deductTxFees", true)
+	viper.SetDefault("voters.multipayment", false)
 	viper.SetDefault("voters.blocklist", "")
 	viper.SetDefault("voters.capBalance", false)
 	viper.SetDefault("voters.balanceCapAmount", 0.0)
@@ -161,6 +220,15 @@ func loadConfig() {
 	viper.SetDefault("client.statistics", true)
 	viper.SetDefault("client.statPeer", "164.8.251.91")
 	viper.SetDefault("client.statPort", 54010)
+	viper.SetDefault("client.remoteSigner", "")
+	viper.SetDefault("client.remoteSecondSigner", "")
+	viper.SetDefault("client.remoteSignerToken", "")
+	viper.SetDefault("client.remoteSecondSignerToken", "")
+	viper.SetDefault("client.confirmations", 0)
+	viper.SetDefault("client.confirmationTimeout", 10)
+	viper.SetDefault("client.rebroadcast", false)
+	viper.SetDefault("client.rebroadcastAttempts", core.DefaultRebroadcastAttempts)
+	viper.SetDefault("client.rebroadcastMaxAge", 60)
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -214,6 +282,7 @@ func printMenu() {
 	fmt.Println("\t4-Link account")
 	fmt.Println("\t5-Send bonus payments")
 	fmt.Println("\t6-List payment history")
+	fmt.Println("\t7-Verify voter signed message")
 	fmt.Println("\t0-Exit")
 	fmt.Println("")
 	fmt.Print("\tSelect option [1-9]:")
@@ -236,9 +305,25 @@ func main() {
 
 	initializeBoltClient()
 
-	//switch to preset network
+	//switch to preset network, MAINNET is loaded at init unless no peer responded
+	var err error
 	if viper.GetString("client.network") == "DEVNET" {
-		arkclient = arkclient.SetActiveConfiguration(core.DEVNET)
+		arkclient, err = arkclient.SwitchNetwork(core.DEVNET)
+	} else if !core.EnvironmentParams.Success {
+		arkclient, err = arkclient.SwitchNetwork(core.MAINNET)
+	}
+	if err != nil {
+		color.HiRed("Unable to load network configuration: %s", err.Error())
+		log.Fatal("Unable to load network configuration: ", err.Error())
+	}
+
+	if err := validateConfigAddresses(); err != nil {
+		log.Error("Wrong address in config.toml: ", err.Error())
+		color.HiRed("Wrong address in config.toml: %s", err.Error())
+	}
+	if err := validateConfigMultiPayment(); err != nil {
+		color.HiRed("Wrong config.toml: %s", err.Error())
+		log.Fatal("Wrong config.toml: ", err.Error())
 	}
 
 	//SILENT MODE CHECKING AND AUTOMATION RUNNING
@@ -281,14 +366,24 @@ func main() {
 			wg.Wait()
 			color.Unset()
 		case 3:
-			if core.EnvironmentParams.Network.Type == core.MAINNET {
-				arkclient = arkclient.SetActiveConfiguration(core.DEVNET)
-			} else {
-				arkclient = arkclient.SetActiveConfiguration(core.MAINNET)
+			network := core.ArkNetworkType(core.DEVNET)
+			if core.EnvironmentParams.Network.Type == core.DEVNET {
+				network = core.MAINNET
+			}
+			var err error
+			if arkclient, err = arkclient.SwitchNetwork(network); err != nil {
+				log.Error("Unable to switch network: ", err.Error())
+				color.HiRed("Unable to switch network, staying on %s: %s", core.EnvironmentParams.Network.Type, err.Error())
+				pause()
 			}
 		case 4:
 			clearScreen()
-			save(readAccountData())
+			p1, p2 := readAccountData()
+			if p1 == "error" {
+				pause()
+				break
+			}
+			save(p1, p2)
 			color.Set(color.FgHiGreen)
 			log.Info("Account succesfully linked")
 			fmt.Println("Account succesfully linked")
@@ -327,6 +422,12 @@ func main() {
 			//listPaymentsDetailsFromDB()
 			//pause()
 			color.Unset()
+		case 7:
+			clearScreen()
+			color.Set(color.FgHiGreen)
+			verifySignedMessage()
+			pause()
+			color.Unset()
 		}
 	}
 	color.Unset()
@@ -4,14 +4,26 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/asdine/storm"
 	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/arkcoin/base58"
 	"github.com/kristjank/ark-go/core"
+	"github.com/spf13/viper"
 )
 
 func TestReadAccountData(t *testing.T) {
@@ -116,3 +128,197 @@ func TestSendStatisticsData(t *testing.T) {
 		sendStatisticsData(&payrec)
 	}
 }
+
+func TestVotersFee(t *testing.T) {
+	defer viper.Set("voters.multipayment", false)
+
+	viper.Set("voters.multipayment", false)
+	if votersFee(70) != 70*core.EnvironmentParams.Fees.Send {
+		t.Error("Wrong transfer fee", votersFee(70))
+	}
+	viper.Set("voters.multipayment", true)
+	if votersFee(70) != 2*core.MultiPaymentFee() {
+		t.Error("Wrong multipayment fee", votersFee(70))
+	}
+	if votersFee(1) != core.EnvironmentParams.Fees.Send {
+		t.Error("Wrong fee for single voter", votersFee(1))
+	}
+}
+
+func TestValidateConfigMultiPayment(t *testing.T) {
+	defer viper.Set("voters.multipayment", false)
+	version := core.EnvironmentParams.Network.TransactionVersion
+	defer func() {
+		core.EnvironmentParams.Network.TransactionVersion = version
+	}()
+
+	viper.Set("voters.multipayment", true)
+	core.EnvironmentParams.Network.TransactionVersion = core.TransactionV1
+	if validateConfigMultiPayment() == nil {
+		t.Error("Multipayment accepted on legacy network")
+	}
+	core.EnvironmentParams.Network.TransactionVersion = core.TransactionV2
+	if err := validateConfigMultiPayment(); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestAccountKey(t *testing.T) {
+	params := arkcoin.ActiveCoinConfig
+	arkcoin.SetActiveCoinConfiguration(arkcoin.ArkCoinMain)
+	defer arkcoin.SetActiveCoinConfiguration(params)
+
+	pass := "this is a top secret passphrase"
+	key := arkcoin.NewPrivateKeyFromPassword(pass, arkcoin.ActiveCoinConfig)
+
+	if isWIF(pass) || !isWIF(key.WIFAddress()) {
+		t.Error("Wrong WIF detection")
+	}
+	if accountKey(pass).PublicKey.Address() != key.PublicKey.Address() {
+		t.Error("Wrong passphrase key")
+	}
+	if accountKey(key.WIFAddress()).PublicKey.Address() != key.PublicKey.Address() {
+		t.Error("Wrong WIF key")
+	}
+
+	uncompressed := base58.Encode(append([]byte{arkcoin.ActiveCoinConfig.DumpedPrivateKeyHeader[0]}, key.Serialize()...))
+	if isWIF(uncompressed) || !isUncompressedWIF(uncompressed) || isUncompressedWIF(key.WIFAddress()) {
+		t.Error("Uncompressed WIF accepted")
+	}
+
+	second := arkcoin.NewPrivateKeyFromPassword("second passphrase", arkcoin.ActiveCoinConfig)
+	account := core.AccountData{SecondSignature: 1, SecondPublicKey: hex.EncodeToString(second.PublicKey.SerializeCompressed())}
+	if checkSecondKey("second passphrase", account) != nil || checkSecondKey(second.WIFAddress(), account) != nil {
+		t.Error("Second key rejected")
+	}
+	if checkSecondKey("second pasphrase", account) == nil || checkSecondKey(pass, core.AccountData{}) == nil {
+		t.Error("Wrong second key accepted")
+	}
+}
+
+//flakySigner fails signing at call failAt, or at every call from failAt on when always is set
+type flakySigner struct {
+	core.Signer
+	mu     sync.Mutex
+	calls  int
+	failAt int
+	always bool
+}
+
+func (s *flakySigner) SignHash(hash []byte) ([]byte, error) {
+	s.mu.Lock()
+	s.calls++
+	fail := s.calls == s.failAt || s.always && s.calls >= s.failAt
+	s.mu.Unlock()
+	if fail {
+		return nil, errors.New("signer not responding")
+	}
+	return s.Signer.SignHash(hash)
+}
+
+//useV2Network switches to AIP-11 transactions with nonces from test peer, last sent nonce is 5
+func useV2Network() func() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"address":"` + strings.TrimPrefix(r.URL.Path, "/api/wallets/") + `","nonce":"5"}}`))
+	}))
+	address, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(address.Port())
+
+	params, baseURL, network, nonces := arkcoin.ActiveCoinConfig, core.BaseURL, core.EnvironmentParams.Network, core.Nonces
+	arkcoin.SetActiveCoinConfiguration(arkcoin.ArkCoinMain)
+	core.EnvironmentParams.Network.TransactionVersion = core.TransactionV2
+	core.Nonces = core.NewNonceTracker(core.NewArkClientFromPeer(core.Peer{IP: address.Hostname(), Port: port}))
+	return func() {
+		arkcoin.SetActiveCoinConfiguration(params)
+		core.BaseURL, core.EnvironmentParams.Network, core.Nonces = baseURL, network, nonces
+		server.Close()
+	}
+}
+
+func TestSignVoterTransactions(t *testing.T) {
+	defer useV2Network()()
+
+	templates := make([]core.Transaction, 10)
+	for i := range templates {
+		templates[i] = core.Transaction{Type: core.SENDARK, RecipientID: "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25", Amount: int64(i + 1), Fee: 10000000}
+	}
+	templates[3].RecipientID = "not an address"
+
+	signer := &flakySigner{Signer: core.NewPassphraseSigner("this is a top secret passphrase"), failAt: 5}
+	results, err := signVoterTransactions(templates, signer, nil, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	nonce := uint64(6)
+	for i, result := range results {
+		if i == 3 {
+			if result.Err == nil || result.Prepared {
+				t.Error("Invalid template signed", result)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Fatal(i, result.Err.Error())
+		}
+		if result.Transaction.Nonce != nonce {
+			t.Error("Nonce gap", i, result.Transaction.Nonce, nonce)
+		}
+		nonce++
+	}
+
+	signer = &flakySigner{Signer: core.NewPassphraseSigner("this is a top secret passphrase"), failAt: 5, always: true}
+	if _, err = signVoterTransactions(templates, signer, nil, nil); err == nil {
+		t.Error("Signing error not reported")
+	}
+}
+
+func TestCreateVoterMultiPayments(t *testing.T) {
+	defer useV2Network()()
+	dir, err := ioutil.TempDir("", "arkgopool")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+	db, err := storm.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer db.Close()
+
+	//the delegate voting for itself is skipped, the rest is split into two multipayments
+	signer := &flakySigner{Signer: core.NewPassphraseSigner("this is a top secret passphrase"), failAt: 1}
+	voters := []voterPayment{{core.DelegateDataProfit{Address: signerAddress(signer)}, 100000000}}
+	for i := 0; i < core.MultiPaymentMaxPayments+2; i++ {
+		key := arkcoin.NewPrivateKeyFromPassword(fmt.Sprintf("voter %d", i), arkcoin.ActiveCoinConfig)
+		voters = append(voters, voterPayment{core.DelegateDataProfit{Address: key.PublicKey.Address()}, 100000000})
+	}
+
+	dbtx, err := db.Begin(true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	transactions, err := createVoterMultiPayments(dbtx, voters, signer, nil, 1)
+	dbtx.Rollback()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(transactions) != 2 || len(transactions[0].Payments)+len(transactions[1].Payments) != len(voters)-1 {
+		t.Fatal("Wrong multipayments", len(transactions))
+	}
+	for i, tx := range transactions {
+		if tx.Type != core.MULTIPAYMENT || tx.Nonce != uint64(6+i) || tx.Verify() != nil {
+			t.Error("Wrong multipayment", i, tx.ToJSON())
+		}
+	}
+
+	signer = &flakySigner{Signer: core.NewPassphraseSigner("this is a top secret passphrase"), failAt: 1, always: true}
+	if dbtx, err = db.Begin(true); err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = createVoterMultiPayments(dbtx, voters, signer, nil, 1)
+	dbtx.Rollback()
+	if err == nil {
+		t.Error("Signing error not reported")
+	}
+}
@@ -33,7 +33,7 @@ func rollbackTx(dbtx storm.Node) {
 	}
 }
 
-func save2db(dbtx storm.Node, ve core.DelegateDataProfit, tx *core.Transaction, relID int) {
+func save2db(dbtx storm.Node, ve core.DelegateDataProfit, tx *core.Transaction, index int, relID int) {
 	dbData := model.PaymentLogRecord{}
 
 	dbData.Address = ve.Address
@@ -43,6 +43,11 @@ func save2db(dbtx storm.Node, ve core.DelegateDataProfit, tx *core.Transaction,
 	dbData.EarnedAmountXX = ve.EarnedAmountXX
 	dbData.VoteDuration = ve.VoteDuration
 	dbData.Transaction = *tx
+	dbData.Amount = tx.Amount
+	if tx.Type == core.MULTIPAYMENT {
+		dbData.Amount = tx.Payments[index].Amount
+		dbData.PaymentIndex = index
+	}
 	dbData.PaymentRecordID = relID
 	dbData.CreatedAt = time.Now()
 
@@ -57,6 +62,7 @@ func savebonus2db(dbtx storm.Node, address string, tx *core.Transaction, relID i
 
 	dbData.Address = address
 	dbData.Transaction = *tx
+	dbData.Amount = tx.Amount
 	dbData.PaymentRecordID = relID
 	dbData.CreatedAt = time.Now()
 
@@ -109,6 +115,7 @@ func createPaymentRecord() model.PaymentRecord {
 		ReserveRatio:     viper.GetFloat64("reserve.shareratio"),
 		CreatedAt:        time.Now().UTC(),
 		FeeDeduction:     viper.GetBool("voters.deductTxFees"),
+		MultiPayment:     viper.GetBool("voters.multipayment"),
 		Fidelity:         viper.GetBool("voters.fidelity"),
 		FidelityLimit:    viper.GetInt("voters.fidelityLimit"),
 		MinAmount:        viper.GetFloat64("voters.minamount"),
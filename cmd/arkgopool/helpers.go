@@ -7,14 +7,17 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/kristjank/ark-go/arkcoin"
 	"github.com/kristjank/ark-go/core"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -41,6 +44,114 @@ func checkConfigSharingRatio() bool {
 	return true
 }
 
+//validateConfigAddresses checks all configured payout addresses against the active network,
+//so an address from another network is caught before any transaction is signed
+func validateConfigAddresses() error {
+	keys := []string{"delegate.address", "costs.address", "reserve.address", "personal.address"}
+	if core.EnvironmentParams.Network.Type == core.DEVNET {
+		keys = []string{"delegate.Daddress", "costs.Daddress", "reserve.Daddress", "personal.Daddress"}
+	}
+
+	for _, key := range keys {
+		addr := strings.TrimSpace(viper.GetString(key))
+		if addr == "" {
+			continue
+		}
+		if err := arkcoin.ValidateAddress(addr, arkcoin.ActiveCoinConfig); err != nil {
+			return fmt.Errorf("config %s=%s: %s", key, addr, err.Error())
+		}
+	}
+	return nil
+}
+
+//validateConfigMultiPayment checks voters.multipayment against the active network, multipayment is
+//an AIP-11 transaction type and legacy networks never confirm it
+func validateConfigMultiPayment() error {
+	if viper.GetBool("voters.multipayment") && core.EnvironmentParams.Network.TransactionVersion < core.TransactionV2 {
+		return fmt.Errorf("config voters.multipayment=true: %s", core.ErrMultiPaymentVersion.Error())
+	}
+	return nil
+}
+
+//loadSigners returns delegate signers. Remote signer is used when client.remoteSigner is set,
+//otherwise linked account data or the entered passphrases. linked is false for entered passphrases.
+func loadSigners() (signer, secondSigner core.Signer, linked bool, err error) {
+	if address := viper.GetString("client.remoteSigner"); address != "" {
+		log.Info("Using remote signer ", address)
+		remote, err := core.NewRemoteSigner(address, viper.GetString("client.remoteSignerToken"))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if address = viper.GetString("client.remoteSecondSigner"); address != "" {
+			remoteSecond, err := core.NewRemoteSigner(address, viper.GetString("client.remoteSecondSignerToken"))
+			if err != nil {
+				return nil, nil, false, err
+			}
+			return remote, remoteSecond, true, nil
+		}
+		return remote, nil, true, nil
+	}
+
+	var p1, p2 string
+	if _, err := os.Stat("assembly.ark"); err == nil {
+		log.Info("Linked accound data found. Using saved account information.")
+		p1, p2 = read()
+		linked = true
+	} else {
+		p1, p2 = readAccountData()
+		if p1 == "error" {
+			return nil, nil, false, errors.New("unable to read account data")
+		}
+	}
+
+	signer = core.NewKeySigner(accountKey(p1))
+	if p2 != "" {
+		secondSigner = core.NewKeySigner(accountKey(p2))
+	}
+	return signer, secondSigner, linked, nil
+}
+
+//isWIF reports whether account secret is private key in compressed WIF format of the active network
+func isWIF(secret string) bool {
+	_, err := core.NewWIFSigner(secret)
+	return err == nil
+}
+
+//isUncompressedWIF reports whether account secret is private key in uncompressed WIF format, which is
+//not an Ark key
+func isUncompressedWIF(secret string) bool {
+	_, err := core.NewWIFSigner(secret)
+	return err == core.ErrWIFUncompressed
+}
+
+//accountKey returns private key of entered or linked account secret, which is WIF or passphrase
+func accountKey(secret string) *arkcoin.PrivateKey {
+	if isWIF(secret) {
+		key, _ := arkcoin.FromWIF(secret, arkcoin.ActiveCoinConfig)
+		return key
+	}
+	return arkcoin.NewPrivateKeyFromPassword(secret, arkcoin.ActiveCoinConfig)
+}
+
+//checkSecondKey checks that entered second secret is the key of account second signature, wrong
+//second key makes every transaction of the account invalid
+func checkSecondKey(secret string, account core.AccountData) error {
+	secondPublicKey, _ := account.SecondPublicKey.(string)
+	if hex.EncodeToString(accountKey(secret).PublicKey.SerializeCompressed()) != secondPublicKey {
+		return errors.New("entered second secret does not match the second public key of the account")
+	}
+	return nil
+}
+
+//signerAddress returns delegate address of signer on active network
+func signerAddress(signer core.Signer) string {
+	pub, err := arkcoin.NewPublicKey(signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return ""
+	}
+	return pub.Address()
+}
+
 func log2csv(payload core.TransactionPayload, txids []string, fileName string, status string) {
 	filecsv, _ := os.Create(fileName)
 
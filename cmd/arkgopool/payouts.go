@@ -6,7 +6,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/asdine/storm"
 	"github.com/fatih/color"
 	"github.com/kristjank/ark-go/arkcoin"
 	"github.com/kristjank/ark-go/core"
@@ -27,7 +29,7 @@ func DisplayCalculatedVoteRatio() {
 	if _, err := os.Stat("assembly.ark"); err == nil {
 		log.Info("Linked accound data found. Using saved account information.")
 		p1, _ = read()
-		key1 = arkcoin.NewPrivateKeyFromPassword(p1, arkcoin.ActiveCoinConfig)
+		key1 = accountKey(p1)
 		pubKey = hex.EncodeToString(key1.PublicKey.Serialize())
 		isLinked = true
 	}
@@ -57,6 +59,9 @@ func DisplayCalculatedVoteRatio() {
 	fmt.Print("\tfee deduction:")
 	color.HiRed("%t", viper.GetBool("voters.deductTxFees"))
 	color.Set(color.FgHiYellow)
+	fmt.Print("\tmultipayment:")
+	color.HiRed("%t", viper.GetBool("voters.multipayment"))
+	color.Set(color.FgHiYellow)
 	fmt.Print("\tcap balance:")
 	color.HiRed("%t", viper.GetBool("voters.capBalance"))
 	color.Set(color.FgHiYellow)
@@ -90,7 +95,7 @@ func DisplayCalculatedVoteRatio() {
 	//must be at this spot - as it counts the number of voters to get the rewards - befor other
 	//transactions are added...
 	if !viper.GetBool("voters.deductTxFees") {
-		feeAmount = float64(int(len(votersEarnings))*int(core.EnvironmentParams.Fees.Send)) / float64(core.SATOSHI)
+		feeAmount = float64(votersFee(len(votersEarnings))) / float64(core.SATOSHI)
 		log.Info("Calculated fee amount: ", feeAmount)
 
 		//deducting feeAmount from reserve address
@@ -138,26 +143,47 @@ func SendPayments(silent bool) {
 		return
 	}
 
-	isLinked := false
+	if err := validateConfigAddresses(); err != nil {
+		color.Set(color.FgHiRed)
+		log.Error("Wrong address in config.toml: ", err.Error())
+		if !silent {
+			fmt.Println("Wrong address in config.toml:", err.Error())
+			pause()
+		}
+		rollbackTx(dbtx)
+		broadCastServiceMode(false)
+		return
+	}
+
+	if err := validateConfigMultiPayment(); err != nil {
+		log.Error("Wrong config.toml: ", err.Error())
+		if !silent {
+			color.HiRed("Wrong config.toml: %s", err.Error())
+			pause()
+		}
+		rollbackTx(dbtx)
+		broadCastServiceMode(false)
+		return
+	}
+
 	pubKey := viper.GetString("delegate.pubkey")
 	if core.EnvironmentParams.Network.Type == core.DEVNET {
 		pubKey = viper.GetString("delegate.Dpubkey")
 	}
 
-	var p1, p2 string
-	var key1 *arkcoin.PrivateKey
-	if _, err := os.Stat("assembly.ark"); err == nil {
-		log.Info("Linked accound data found. Using saved account information.")
-
-		p1, p2 = read()
-
-		key1 = arkcoin.NewPrivateKeyFromPassword(p1, arkcoin.ActiveCoinConfig)
-		pubKey = hex.EncodeToString(key1.PublicKey.Serialize())
-
-		isLinked = true
-	} else {
-		p1, p2 = readAccountData()
-		key1 = arkcoin.NewPrivateKeyFromPassword(p1, arkcoin.ActiveCoinConfig)
+	signer, secondSigner, isLinked, err := loadSigners()
+	if err != nil {
+		rollbackTx(dbtx)
+		log.Error("Unable to load delegate signer: ", err.Error())
+		if !silent {
+			color.HiRed("Unable to load delegate signer: %s", err.Error())
+			pause()
+		}
+		broadCastServiceMode(false)
+		return
+	}
+	if isLinked {
+		pubKey = hex.EncodeToString(signer.PublicKey())
 	}
 
 	//TODO JARUNIK TEST
@@ -178,6 +204,10 @@ func SendPayments(silent bool) {
 	sumShareEarned := 0.0
 	feeAmount := 0.0
 	minAmountSetting := int64(viper.GetFloat64("voters.minamount") * core.SATOSHI)
+	multiPayment := viper.GetBool("voters.multipayment")
+	var pendingPayments []voterPayment
+	var payoutVoters []core.DelegateDataProfit
+	var templates []core.Transaction
 
 	clearScreen()
 
@@ -192,22 +222,68 @@ func SendPayments(silent bool) {
 		//transaction parameters
 		txAmount2Send := int64(fAmount2Send * core.SATOSHI)
 
-		//decuting fees if setup
+		//decuting fees if setup, multipayment voters pay their share of transaction fee when packed
+		feeDeduction := int64(0)
 		if viper.GetBool("voters.deductTxFees") {
-			txAmount2Send -= core.EnvironmentParams.Fees.Send
+			feeDeduction = core.EnvironmentParams.Fees.Send
+			if multiPayment {
+				//checking minimum against the biggest possible fee share
+				feeDeduction = maxMultiPaymentFeeShare()
+			}
 			log.Info("Voters Fee deduction enabled")
 		}
 
 		//checking MinAmount && MaxAmount properties
-		if txAmount2Send > minAmountSetting && txAmount2Send > 0 {
-			tx := core.CreateTransaction(element.Address, txAmount2Send, viper.GetString("voters.txdescription"), p1, p2)
-			payload.Transactions = append(payload.Transactions, tx)
-			//Logging history to DB
-			save2db(dbtx, element, tx, payrec.Pk)
+		if txAmount2Send-feeDeduction > minAmountSetting && txAmount2Send-feeDeduction > 0 {
+			if multiPayment {
+				pendingPayments = append(pendingPayments, voterPayment{element, txAmount2Send})
+				continue
+			}
+			payoutVoters = append(payoutVoters, element)
+			templates = append(templates, core.Transaction{
+				Type:        core.SENDARK,
+				RecipientID: element.Address,
+				Amount:      txAmount2Send - feeDeduction,
+				Fee:         core.EnvironmentParams.Fees.Send,
+				VendorField: viper.GetString("voters.txdescription"),
+			})
 		} else {
-			log.Info("Skipping voter address ", element.Address, " Earned amount: ", txAmount2Send, " below minimium: ", minAmountSetting)
+			log.Info("Skipping voter address ", element.Address, " Earned amount: ", txAmount2Send-feeDeduction, " below minimium: ", minAmountSetting)
+		}
+	}
+
+	//signing voter transactions at once, results are in order of voters
+	var progress core.BulkProgress
+	if !silent {
+		progress = func(done, total int) {
+			fmt.Printf("\rSigning voter transactions %d/%d", done, total)
+		}
+	}
+	start := time.Now()
+	results, err := signVoterTransactions(templates, signer, secondSigner, progress)
+	if err != nil {
+		rollbackTx(dbtx)
+		log.Fatal("Unable to sign voter transactions, payment script stopped: ", err.Error())
+	}
+	for ix, result := range results {
+		if result.Err != nil {
+			log.Error("Skipping voter address ", payoutVoters[ix].Address, " ", result.Err.Error())
+			continue
 		}
+		payload.Transactions = append(payload.Transactions, result.Transaction)
+		//Logging history to DB
+		save2db(dbtx, payoutVoters[ix], result.Transaction, 0, payrec.Pk)
+	}
+	if !silent && len(templates) > 0 {
+		fmt.Println()
+	}
+	log.Info("Signed ", len(templates), " voter transactions in ", time.Since(start))
+	multiPayments, err := createVoterMultiPayments(dbtx, pendingPayments, signer, secondSigner, payrec.Pk)
+	if err != nil {
+		rollbackTx(dbtx)
+		log.Fatal("Unable to sign voter multipayments, payment script stopped: ", err.Error())
 	}
+	payload.Transactions = append(payload.Transactions, multiPayments...)
 
 	//Cost & reserve fund calculation
 	costAmount := sumEarned * viper.GetFloat64("costs.shareratio")
@@ -218,7 +294,7 @@ func SendPayments(silent bool) {
 	//must be at this spot - as it counts the number of voters to get the rewards - befor other
 	//transactions are added, and only voters with enough big share to payout
 	if !viper.GetBool("voters.deductTxFees") {
-		feeAmount = float64(int(len(payload.Transactions))*int(core.EnvironmentParams.Fees.Send)) / float64(core.SATOSHI)
+		feeAmount = float64(transactionsFee(payload.Transactions)) / float64(core.SATOSHI)
 		log.Info("Calculated fee amount: ", feeAmount)
 		payrec.FeeAmount = feeAmount
 
@@ -265,7 +341,11 @@ func SendPayments(silent bool) {
 			costAddress = viper.GetString("costs.Daddress")
 		}
 
-		txCosts := core.CreateTransaction(costAddress, costAmount2Send, viper.GetString("costs.txdescription"), p1, p2)
+		txCosts, err := core.CreateTransactionWithSigner(costAddress, costAmount2Send, viper.GetString("costs.txdescription"), signer, secondSigner)
+		if err != nil {
+			rollbackTx(dbtx)
+			log.Fatal("Unable to create transaction for ", costAddress, " ", err.Error())
+		}
 		payload.Transactions = append(payload.Transactions, txCosts)
 	}
 
@@ -276,7 +356,11 @@ func SendPayments(silent bool) {
 		if core.EnvironmentParams.Network.Type == core.DEVNET {
 			reserveAddress = viper.GetString("reserve.Daddress")
 		}
-		txReserve := core.CreateTransaction(reserveAddress, reserveAmount2Send, viper.GetString("reserve.txdescription"), p1, p2)
+		txReserve, err := core.CreateTransactionWithSigner(reserveAddress, reserveAmount2Send, viper.GetString("reserve.txdescription"), signer, secondSigner)
+		if err != nil {
+			rollbackTx(dbtx)
+			log.Fatal("Unable to create transaction for ", reserveAddress, " ", err.Error())
+		}
 		payload.Transactions = append(payload.Transactions, txReserve)
 	}
 
@@ -287,12 +371,16 @@ func SendPayments(silent bool) {
 		if core.EnvironmentParams.Network.Type == core.DEVNET {
 			personalAddress = viper.GetString("personal.Daddress")
 		}
-		txpersonal := core.CreateTransaction(personalAddress, personalAmount2Send, viper.GetString("personal.txdescription"), p1, p2)
+		txpersonal, err := core.CreateTransactionWithSigner(personalAddress, personalAmount2Send, viper.GetString("personal.txdescription"), signer, secondSigner)
+		if err != nil {
+			rollbackTx(dbtx)
+			log.Fatal("Unable to create transaction for ", personalAddress, " ", err.Error())
+		}
 		payload.Transactions = append(payload.Transactions, txpersonal)
 	}
 
 	payrec.NrOfTransactions = len(payload.Transactions)
-	payrec.FeeAmount = float64(int(len(payload.Transactions))*int(core.EnvironmentParams.Fees.Send)) / float64(core.SATOSHI)
+	payrec.FeeAmount = float64(transactionsFee(payload.Transactions)) / float64(core.SATOSHI)
 
 	dbtx.Update(&payrec)
 
@@ -300,7 +388,7 @@ func SendPayments(silent bool) {
 	fmt.Println("--------------------------------------------------------------------------------------------------------------")
 	fmt.Println("Transactions to be sent from:")
 	color.Set(color.FgHiYellow)
-	fmt.Println("\tDelegate address:", key1.PublicKey.Address())
+	fmt.Println("\tDelegate address:", signerAddress(signer))
 	color.Set(color.FgHiYellow)
 	fmt.Print("\tFidelity:")
 	color.HiRed("%t", viper.GetBool("voters.fidelity"))
@@ -308,6 +396,9 @@ func SendPayments(silent bool) {
 	fmt.Print("\tFee deduction:")
 	color.HiRed("%t", viper.GetBool("voters.deductTxFees"))
 	color.Set(color.FgHiYellow)
+	fmt.Print("\tMultipayment:")
+	color.HiRed("%t", multiPayment)
+	color.Set(color.FgHiYellow)
 	fmt.Print("\tcap balance:")
 	color.HiRed("%t", viper.GetBool("voters.capBalance"))
 	color.Set(color.FgHiYellow)
@@ -321,7 +412,11 @@ func SendPayments(silent bool) {
 	fmt.Println("--------------------------------------------------------------------------------------------------------------")
 	color.Set(color.FgHiCyan)
 	for ix, el := range payload.Transactions {
-		s := fmt.Sprintf("%3d.|%s|%15d| %-40s|", ix+1, el.RecipientID, el.Amount, el.VendorField)
+		recipient := el.RecipientID
+		if el.Type == core.MULTIPAYMENT {
+			recipient = fmt.Sprintf("multipayment to %d voters", len(el.Payments))
+		}
+		s := fmt.Sprintf("%3d.|%-34s|%15d| %-40s|", ix+1, recipient, el.TotalAmount(), el.VendorField)
 		fmt.Println(s)
 		log.Info(s)
 	}
@@ -345,12 +440,17 @@ func SendPayments(silent bool) {
 		fmt.Println("Sending rewards to voters and sharing accounts.............")
 		log.Info("Starting automated payment... ")
 
-		splitAndDeliverPayload(payload)
+		logFolder := splitAndDeliverPayload(payload)
 		if viper.GetBool("client.statistics") {
 			go sendStatisticsData(&payrec)
 		}
 		commitTx(dbtx)
 
+		if depth := payoutConfirmationDepth(); depth > 0 {
+			wg.Wait()
+			waitForPayoutConfirmations(payload, depth, logFolder)
+		}
+
 		fmt.Println("Automated Payment complete. Please check the logs folder... ")
 		log.Info("Automated Payment complete. Please check the logs folder... ")
 
@@ -361,7 +461,151 @@ func SendPayments(silent bool) {
 
 	} else {
 		rollbackTx(dbtx)
+		//created transactions are not sent, their nonces can be used again
+		core.Nonces.Reset(hex.EncodeToString(signer.PublicKey()))
+	}
+}
+
+//voterPayment is voter payout waiting to be packed into multipayment transaction
+type voterPayment struct {
+	voter  core.DelegateDataProfit
+	amount int64
+}
+
+//signVoterTransactions signs voter templates with core.SignBulk. A signing error leaves a nonce gap on
+//AIP-11 networks, so all templates are signed again with nonces fetched again. Error is returned when
+//signing fails again, invalid templates are reported in results.
+func signVoterTransactions(templates []core.Transaction, signer, secondSigner core.Signer, progress core.BulkProgress) ([]core.BulkResult, error) {
+	for attempt := 1; ; attempt++ {
+		results := core.SignBulk(templates, signer, secondSigner, 0, progress)
+		if core.EnvironmentParams.Network.TransactionVersion < core.TransactionV2 {
+			return results, nil
+		}
+		var gap error
+		for _, result := range results {
+			if result.Err != nil && result.Prepared {
+				gap = result.Err
+				break
+			}
+		}
+		if gap == nil {
+			return results, nil
+		}
+		core.Nonces.Reset(hex.EncodeToString(signer.PublicKey()))
+		if attempt == 2 {
+			return nil, gap
+		}
+		log.Warn("Signing failed, signing all voter transactions again: ", gap.Error())
+	}
+}
+
+//maxMultiPaymentFeeShare returns the biggest multipayment fee share a voter can pay, when sharing with one voter
+func maxMultiPaymentFeeShare() int64 {
+	return (core.MultiPaymentFee() + core.MultiPaymentMinPayments - 1) / core.MultiPaymentMinPayments
+}
+
+//votersFee returns fee of paying nrOfVoters voters, with one multipayment for up to 64 voters if enabled
+func votersFee(nrOfVoters int) int64 {
+	if !viper.GetBool("voters.multipayment") {
+		return int64(nrOfVoters) * core.EnvironmentParams.Fees.Send
+	}
+	fee := int64(0)
+	for _, group := range core.SplitPayments(make([]core.Payment, nrOfVoters)) {
+		if len(group) < core.MultiPaymentMinPayments {
+			fee += core.EnvironmentParams.Fees.Send
+		} else {
+			fee += core.MultiPaymentFee()
+		}
 	}
+	return fee
+}
+
+//transactionsFee returns sum of transaction fees
+func transactionsFee(transactions []*core.Transaction) int64 {
+	fee := int64(0)
+	for _, tx := range transactions {
+		fee += tx.Fee
+	}
+	return fee
+}
+
+//createVoterMultiPayments packs voter payouts into as few multipayment transactions as possible and logs
+//every voter line item to DB. With fee deduction voters of a transaction share its fee equally.
+//Single voter left (there is only one voter to pay) gets an ordinary transfer. Transactions are signed
+//with signVoterTransactions, error is returned when they can not be signed without a nonce gap.
+func createVoterMultiPayments(dbtx storm.Node, voters []voterPayment, signer, secondSigner core.Signer, relID int) ([]*core.Transaction, error) {
+	//multipayment to the delegate itself is invalid for the whole group
+	senderAddress := signerAddress(signer)
+	var payments []core.Payment
+	var recipients []voterPayment
+	for _, v := range voters {
+		if v.voter.Address == senderAddress {
+			log.Info("Skipping voter address ", v.voter.Address, " ", core.ErrSelfSend.Error())
+			continue
+		}
+		payments = append(payments, core.Payment{Amount: v.amount, RecipientID: v.voter.Address})
+		recipients = append(recipients, v)
+	}
+
+	var templates []core.Transaction
+	var groupVoters [][]voterPayment
+	offset := 0
+	for _, group := range core.SplitPayments(payments) {
+		groupRecipients := recipients[offset : offset+len(group)]
+		offset += len(group)
+
+		if len(group) < core.MultiPaymentMinPayments {
+			amount := group[0].Amount
+			if viper.GetBool("voters.deductTxFees") {
+				amount -= core.EnvironmentParams.Fees.Send
+			}
+			if amount <= 0 {
+				log.Info("Skipping voter address ", group[0].RecipientID, " Earned amount: ", amount, " below transaction fee")
+				continue
+			}
+			templates = append(templates, core.Transaction{
+				Type:        core.SENDARK,
+				RecipientID: group[0].RecipientID,
+				Amount:      amount,
+				Fee:         core.EnvironmentParams.Fees.Send,
+				VendorField: viper.GetString("voters.txdescription"),
+			})
+			groupVoters = append(groupVoters, groupRecipients)
+			continue
+		}
+
+		if viper.GetBool("voters.deductTxFees") {
+			share := (core.MultiPaymentFee() + int64(len(group)) - 1) / int64(len(group))
+			for i := range group {
+				group[i].Amount -= share
+			}
+		}
+		templates = append(templates, core.Transaction{
+			Type:        core.MULTIPAYMENT,
+			Fee:         core.MultiPaymentFee(),
+			VendorField: viper.GetString("voters.txdescription"),
+			Payments:    group,
+		})
+		groupVoters = append(groupVoters, groupRecipients)
+	}
+
+	results, err := signVoterTransactions(templates, signer, secondSigner, nil)
+	if err != nil {
+		return nil, err
+	}
+	var transactions []*core.Transaction
+	for ix, result := range results {
+		if result.Err != nil {
+			log.Error("Skipping payment to ", len(groupVoters[ix]), " voters ", result.Err.Error())
+			continue
+		}
+		transactions = append(transactions, result.Transaction)
+		//Logging every voter line item to DB
+		for i, v := range groupVoters[ix] {
+			save2db(dbtx, v.voter, result.Transaction, i, relID)
+		}
+	}
+	return transactions, nil
 }
 
 func calcFidelity(element core.DelegateDataProfit) float64 {
@@ -390,19 +634,16 @@ func SendBonusPayment(iAmount int, txDesc string) {
 		pubKey = viper.GetString("delegate.Dpubkey")
 	}
 
-	var p1, p2 string
-	var key1 *arkcoin.PrivateKey
-	if _, err := os.Stat("assembly.ark"); err == nil {
-		log.Info("Linked accound data found. Using saved account information.")
-
-		p1, p2 = read()
-
-		key1 = arkcoin.NewPrivateKeyFromPassword(p1, arkcoin.ActiveCoinConfig)
-		pubKey = hex.EncodeToString(key1.PublicKey.Serialize())
-
-	} else {
-		p1, p2 = readAccountData()
-		key1 = arkcoin.NewPrivateKeyFromPassword(p1, arkcoin.ActiveCoinConfig)
+	signer, secondSigner, isLinked, err := loadSigners()
+	if err != nil {
+		rollbackTx(dbtx)
+		log.Error("Unable to load delegate signer: ", err.Error())
+		color.HiRed("Unable to load delegate signer: %s", err.Error())
+		pause()
+		return
+	}
+	if isLinked {
+		pubKey = hex.EncodeToString(signer.PublicKey())
 	}
 
 	//TODO JARUNIK TEST
@@ -432,7 +673,11 @@ func SendBonusPayment(iAmount int, txDesc string) {
 			continue
 		}
 		//transaction parameters
-		tx := core.CreateTransaction(element.Address, txAmount2Send, txDesc, p1, p2)
+		tx, err := core.CreateTransactionWithSigner(element.Address, txAmount2Send, txDesc, signer, secondSigner)
+		if err != nil {
+			log.Error("Skipping bonus payment for ", element.Address, " ", err.Error())
+			continue
+		}
 		payload.Transactions = append(payload.Transactions, tx)
 		//Logging history to DB
 		savebonus2db(dbtx, element.Address, tx, payrec.Pk)
@@ -449,7 +694,7 @@ func SendBonusPayment(iAmount int, txDesc string) {
 	fmt.Println("--------------------------------------------------------------------------------------------------------------")
 	fmt.Println("Transactions to be sent from:")
 	color.Set(color.FgHiYellow)
-	fmt.Println("\tDelegate address:", key1.PublicKey.Address())
+	fmt.Println("\tDelegate address:", signerAddress(signer))
 
 	color.Set(color.FgHiGreen)
 	fmt.Println("--------------------------------------------------------------------------------------------------------------")
@@ -473,19 +718,26 @@ func SendBonusPayment(iAmount int, txDesc string) {
 		fmt.Println("Sending BONUS to VOTERS.............")
 		log.Info("Starting automated payment... ")
 
-		splitAndDeliverPayload(payload)
+		logFolder := splitAndDeliverPayload(payload)
 
 		if viper.GetBool("client.statistics") {
 			go sendStatisticsData(&payrec)
 		}
 		commitTx(dbtx)
 
+		if depth := payoutConfirmationDepth(); depth > 0 {
+			wg.Wait()
+			waitForPayoutConfirmations(payload, depth, logFolder)
+		}
+
 		fmt.Println("Automated Payment complete. Please check the logs folder... ")
 		log.Info("Automated Payment complete. Please check the logs folder... ")
 
 		reader.ReadString('\n')
 	} else {
 		rollbackTx(dbtx)
+		//created transactions are not sent, their nonces can be used again
+		core.Nonces.Reset(hex.EncodeToString(signer.PublicKey()))
 	}
 }
 
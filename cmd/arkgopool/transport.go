@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"time"
 
 	"github.com/dghubble/sling"
 	"github.com/fatih/color"
@@ -30,7 +34,8 @@ func sendStatisticsData(payRec *model.PaymentRecord) {
 	}
 }
 
-func splitAndDeliverPayload(payload core.TransactionPayload) {
+//splitAndDeliverPayload sends payload in chunks to random peers and returns the payout log folder
+func splitAndDeliverPayload(payload core.TransactionPayload) string {
 	//calculating number of chunks (based on 20tx in one chunk to send to one peer)
 	payoutsFolderName := createLogFolder()
 	var divided [][]*core.Transaction
@@ -65,6 +70,7 @@ func splitAndDeliverPayload(payload core.TransactionPayload) {
 	if splitcout != len(payload.Transactions) {
 		log.Info("TX spliting not OK")
 	}
+	return payoutsFolderName
 }
 
 func deliverPayloadThreaded(tmpPayload core.TransactionPayload, chunkIx int, logFolder string) {
@@ -95,3 +101,65 @@ func deliverPayloadThreaded(tmpPayload core.TransactionPayload, chunkIx int, log
 		}(tmpPayload, peers[i], chunkIx, logFolder)
 	}
 }
+
+//payoutConfirmationDepth returns confirmations to wait for after payouts, 0 disables waiting.
+//Rebroadcasting needs the transactions tracked, at least one confirmation is waited for then.
+func payoutConfirmationDepth() int {
+	depth := viper.GetInt("client.confirmations")
+	if depth <= 0 && viper.GetBool("client.rebroadcast") {
+		return 1
+	}
+	return depth
+}
+
+//waitForPayoutConfirmations tracks sent transactions until they have depth confirmations or
+//client.confirmationTimeout minutes passed, the report is written to the payout log folder.
+//With client.rebroadcast dropped transactions are sent again to other peers.
+func waitForPayoutConfirmations(payload core.TransactionPayload, depth int, logFolder string) core.ConfirmationReport {
+	ids := make([]string, len(payload.Transactions))
+	for ix, tx := range payload.Transactions {
+		ids[ix] = tx.ID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("client.confirmationTimeout"))*time.Minute)
+	defer cancel()
+
+	var peers []core.Peer
+	if len(core.EnvironmentParams.Network.PeerList) > 0 {
+		peers = arkclient.GetRandomXPeers(3)
+	}
+	onStatus := func(status core.TransactionStatus) {
+		log.Info("Transaction ", status)
+	}
+	fmt.Println("Waiting for", depth, "confirmations of", len(ids), "transactions.............")
+	log.Info("Waiting for ", depth, " confirmations of ", len(ids), " transactions")
+
+	var report core.ConfirmationReport
+	var err error
+	if viper.GetBool("client.rebroadcast") {
+		rebroadcaster := core.NewRebroadcaster(depth, peers...)
+		rebroadcaster.Tracker.OnStatus = onStatus
+		rebroadcaster.MaxAttempts = viper.GetInt("client.rebroadcastAttempts")
+		rebroadcaster.MaxAge = time.Duration(viper.GetInt("client.rebroadcastMaxAge")) * time.Minute
+		log.Info("Rebroadcasting dropped transactions, max ", rebroadcaster.MaxAttempts, " attempts")
+		report, err = rebroadcaster.Watch(ctx, payload.Transactions)
+	} else {
+		tracker := core.NewConfirmationTracker(depth, peers...)
+		tracker.OnStatus = onStatus
+		report, err = tracker.Wait(ctx, ids)
+	}
+	if err != nil {
+		log.Warn("Transactions not confirmed in time: ", err.Error())
+	}
+	if report.Confirmed() {
+		color.HiGreen("Confirmation report: %s", report)
+	} else {
+		color.HiRed("Confirmation report: %s", report)
+	}
+	log.Info("Confirmation report: ", report)
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	if err = ioutil.WriteFile(fmt.Sprintf("log/%s/confirmations.json", logFolder), data, 0644); err != nil {
+		log.Error("Unable to save confirmation report: ", err.Error())
+	}
+	return report
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kristjank/ark-go/arkcoin/qrcode"
+	"github.com/kristjank/ark-go/core"
+	"github.com/spf13/viper"
+)
+
+//payMeTargets are config sections with addresses that can receive payments
+var payMeTargets = []string{"delegate", "costs", "reserve"}
+
+var errPayMeTarget = errors.New("unknown or not configured pay-me address")
+
+//payMeAddress returns configured address of target for the active network
+func payMeAddress(target string) string {
+	for _, t := range payMeTargets {
+		if t != target {
+			continue
+		}
+		if core.EnvironmentParams.Network.Type == core.DEVNET {
+			return strings.TrimSpace(viper.GetString(target + ".Daddress"))
+		}
+		return strings.TrimSpace(viper.GetString(target + ".address"))
+	}
+	return ""
+}
+
+//payMeURI returns ark: payment URI for target, with optional amount, vendorField and label query parameters
+func payMeURI(c *gin.Context) (string, error) {
+	address := payMeAddress(c.Param("target"))
+	if address == "" {
+		return "", errPayMeTarget
+	}
+	params := url.Values{}
+	for _, key := range []string{"amount", "vendorField", "label"} {
+		if value := c.Query(key); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	tx, label, err := core.ParsePaymentURI(core.PaymentURIScheme + ":" + address + "?" + params.Encode())
+	if err != nil {
+		return "", err
+	}
+	return core.PaymentURI(tx, label)
+}
+
+//GetPayMeList Returns payment URIs of all configured delegate, costs and reserve addresses
+//URL sample: http://localhost:54000/payme
+func GetPayMeList(c *gin.Context) {
+	uris := gin.H{}
+	for _, target := range payMeTargets {
+		address := payMeAddress(target)
+		if address == "" {
+			continue
+		}
+		if uri, err := core.PaymentURI(&core.Transaction{RecipientID: address}, ""); err == nil {
+			uris[target] = uri
+		}
+	}
+	c.JSON(200, gin.H{"success": true, "data": uris})
+}
+
+//GetPayMe Returns payment URI for delegate, costs or reserve address
+//URL sample: http://localhost:54000/payme/delegate?amount=10&vendorField=donation&label=ark-go
+func GetPayMe(c *gin.Context) {
+	uri, err := payMeURI(c)
+	if err != nil {
+		c.JSON(200, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"success": true, "address": payMeAddress(c.Param("target")), "uri": uri})
+}
+
+//GetPayMeQR Returns payment URI as QR code image, PNG or SVG (format=svg)
+//URL sample: http://localhost:54000/payme/reserve/qr?amount=10&format=svg
+func GetPayMeQR(c *gin.Context) {
+	uri, err := payMeURI(c)
+	if err != nil {
+		c.JSON(200, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	code, err := qrcode.EncodeString(uri, qrcode.Medium)
+	if err != nil {
+		c.JSON(200, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "svg" {
+		c.Data(200, "image/svg+xml", []byte(code.SVG(8)))
+		return
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, code.Image(8)); err != nil {
+		c.JSON(200, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.Data(200, "image/png", buf.Bytes())
+}
@@ -130,6 +130,12 @@ func initializeRoutes() {
 		deleRoutes.GET("/paymentruns", api.GetDelegatePaymentRecord)
 		deleRoutes.GET("/paymentruns/details", api.GetDelegatePaymentRecordDetails)
 	}
+	payRoutes := router.Group("/payme")
+	{
+		payRoutes.GET("", api.GetPayMeList)
+		payRoutes.GET("/:target", api.GetPayMe)
+		payRoutes.GET("/:target/qr", api.GetPayMeQR)
+	}
 	serviceRoutes := router.Group("/service")
 	serviceRoutes.Use(api.OnlyLocalCallAllowed())
 	{
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+var reader = bufio.NewReader(os.Stdin)
+
+func usage() {
+	fmt.Println("Split delegate passphrase into shares, or combine shares back.")
+	fmt.Println("")
+	fmt.Println("Usage:")
+	fmt.Println("\tarkgoshares split [-parts 5] [-threshold 3] [-key] [-words] [-devnet]")
+	fmt.Println("\tarkgoshares combine [-devnet]")
+	fmt.Println("")
+	fmt.Println("Passphrase and shares are read from standard input.")
+}
+
+func networkParams(devnet bool) *arkcoin.Params {
+	if devnet {
+		return arkcoin.ArkCoinDevTest
+	}
+	return arkcoin.ArkCoinMain
+}
+
+func readLine(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func split(args []string) error {
+	flags := flag.NewFlagSet("split", flag.ExitOnError)
+	parts := flags.Int("parts", 5, "number of shares to create")
+	threshold := flags.Int("threshold", 3, "number of shares needed to rebuild the passphrase")
+	splitKey := flags.Bool("key", false, "split the private key instead of the passphrase")
+	words := flags.Bool("words", false, "encode shares as words instead of base58")
+	devnet := flags.Bool("devnet", false, "use DEVNET addresses")
+	flags.Parse(args)
+
+	passphrase := readLine("Enter account passphrase\n-->")
+	if err := arkcoin.ValidateMnemonic(passphrase); err != nil {
+		color.HiRed("WARNING: entered passphrase is not a valid BIP39 mnemonic: %s", err.Error())
+	}
+	key := arkcoin.NewPrivateKeyFromPassword(passphrase, networkParams(*devnet))
+
+	var shares []*arkcoin.SecretShare
+	var err error
+	if *splitKey {
+		shares, err = arkcoin.SplitPrivateKey(key, *parts, *threshold)
+	} else {
+		shares, err = arkcoin.SplitPassphrase(passphrase, *parts, *threshold)
+	}
+	if err != nil {
+		return err
+	}
+
+	color.HiGreen("\nShares for address %s, any %d of %d rebuild the secret:\n", key.PublicKey.Address(), *threshold, *parts)
+	for _, s := range shares {
+		encoded := s.Base58()
+		if *words {
+			encoded = s.Words()
+		}
+		color.HiYellow("%s", s)
+		fmt.Println(encoded)
+		fmt.Println("")
+	}
+	return nil
+}
+
+func combine(args []string) error {
+	flags := flag.NewFlagSet("combine", flag.ExitOnError)
+	devnet := flags.Bool("devnet", false, "use DEVNET addresses")
+	flags.Parse(args)
+
+	fmt.Println("Enter shares, one per line. Finish with an empty line.")
+	var shares []*arkcoin.SecretShare
+	for {
+		line := readLine("-->")
+		if line == "" {
+			break
+		}
+		s, err := arkcoin.ParseSecretShare(line)
+		if err != nil {
+			color.HiRed("Share not accepted: %s", err.Error())
+			continue
+		}
+		shares = append(shares, s)
+		color.HiGreen("Accepted %s", s)
+	}
+
+	key, err := arkcoin.CombinePrivateKey(shares, networkParams(*devnet))
+	if err != nil {
+		return err
+	}
+	color.HiGreen("\nAddress: %s", key.PublicKey.Address())
+	if passphrase, err := arkcoin.CombinePassphrase(shares); err == nil {
+		fmt.Println("Passphrase:", passphrase)
+	} else {
+		fmt.Println("WIF:", key.WIFAddress())
+	}
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "split":
+		err = split(os.Args[2:])
+	case "combine":
+		err = combine(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		color.HiRed("Error: %s", err.Error())
+		os.Exit(1)
+	}
+}
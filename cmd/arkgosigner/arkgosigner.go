@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/core"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	socket := flag.String("socket", "", "unix socket path to listen on, for example /var/run/arkgosigner.sock")
+	listen := flag.String("listen", "", "tcp address to listen on, for example 127.0.0.1:54020 (requires -token-file)")
+	tokenFile := flag.String("token-file", "", "file with shared secret clients must send, required with -listen")
+	wif := flag.Bool("wif", false, "read private key in WIF format instead of passphrase")
+	devnet := flag.Bool("devnet", false, "use DEVNET")
+	flag.Parse()
+
+	if (*socket == "") == (*listen == "") {
+		fmt.Println("Exactly one of -socket or -listen must be set")
+		flag.Usage()
+		os.Exit(1)
+	}
+	var token string
+	if *tokenFile != "" {
+		data, err := ioutil.ReadFile(*tokenFile)
+		if err != nil {
+			log.Fatal("Unable to read token: ", err.Error())
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if *listen != "" && len(token) < 16 {
+		log.Fatal("-listen requires -token-file with a shared secret of at least 16 characters")
+	}
+	if *devnet {
+		core.NewArkClient(nil).SetActiveConfiguration(core.DEVNET)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	if *wif {
+		fmt.Print("Enter WIF private key\n-->")
+	} else {
+		fmt.Print("Enter account passphrase\n-->")
+	}
+	secret, _ := reader.ReadString('\n')
+	secret = strings.TrimSpace(secret)
+
+	var signer core.Signer
+	if *wif {
+		key, err := core.NewWIFSigner(secret)
+		if err != nil {
+			log.Fatal("Invalid WIF: ", err.Error())
+		}
+		signer = key
+	} else {
+		signer = core.NewPassphraseSigner(secret)
+	}
+
+	pub, _ := arkcoin.NewPublicKey(signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	log.Info("Signing for address ", pub.Address())
+
+	var listener net.Listener
+	var err error
+	if *socket != "" {
+		os.Remove(*socket)
+		//socket is created accessible by the owner only
+		restore := umask(0177)
+		listener, err = net.Listen("unix", *socket)
+		restore()
+	} else {
+		listener, err = net.Listen("tcp", *listen)
+	}
+	if err != nil {
+		log.Fatal("Unable to listen: ", err.Error())
+	}
+	log.Info("Signer listening on ", listener.Addr().String())
+	log.Fatal(http.Serve(listener, core.SignerHandler(signer, token)))
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import "syscall"
+
+//umask sets file mode creation mask and returns function restoring the previous one
+func umask(mask int) func() {
+	previous := syscall.Umask(mask)
+	return func() {
+		syscall.Umask(previous)
+	}
+}
@@ -0,0 +1,6 @@
+package main
+
+//umask does nothing on Windows, there are no file mode creation masks
+func umask(mask int) func() {
+	return func() {}
+}
@@ -17,6 +17,8 @@ type PaymentLogRecord struct {
 	EarnedAmountXX  float64
 	VoteDuration    int
 	Transaction     core.Transaction
+	Amount          int64 //amount paid to Address, line item PaymentIndex of multipayment Transaction
+	PaymentIndex    int
 	PaymentRecordID int       `storm:"index"`
 	CreatedAt       time.Time `storm:"index"`
 }
@@ -35,6 +37,7 @@ type PaymentRecord struct {
 	FidelityLimit    int
 	MinAmount        float64
 	FeeDeduction     bool
+	MultiPayment     bool
 	FeeAmount        float64
 	NrOfTransactions int
 	VoteWeight       int
@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -24,6 +25,14 @@ const (
 	DEVNET
 )
 
+//String returns network type name, MAINNET or DEVNET
+func (t ArkNetworkType) String() string {
+	if t == DEVNET {
+		return "DEVNET"
+	}
+	return "MAINNET"
+}
+
 //TO HELP DIVIDE
 //TODO rename
 const (
@@ -48,6 +57,8 @@ type Fees struct {
 	SecondSignature int64 `json:"secondsignature"`
 	Delegate        int64 `json:"delegate"`
 	MultiSignature  int64 `json:"multisignature"`
+	MultiPayment    int64 `json:"multipayment"`
+	HTLCLock        int64 `json:"htlcLock"`
 }
 
 //Network parameters
@@ -60,17 +71,24 @@ type Network struct {
 	Type           ArkNetworkType //holding ark networktype
 	ActivePeer     Peer
 	PeerList       []Peer
+	//TransactionVersion of new transactions, set from peer version - set TransactionV2 for AIP-11 bridgechains
+	TransactionVersion byte `json:"-"`
 }
 
+//ErrNoPeer is returned when none of the seed peers returns network configuration
+var ErrNoPeer = errors.New("unable to connect to blockchain, no seed peer responded")
+
 //LoadActiveConfiguration reads arknetwork parameters from the Network
 //and fills the EnvironmentParams structure
 //selected and connected peer address is returned
-func LoadActiveConfiguration(arknetwork ArkNetworkType) string {
+//EnvironmentParams are changed only when the whole configuration is read, on error the previous
+//configuration is kept.
+func LoadActiveConfiguration(arknetwork ArkNetworkType) (string, error) {
 	s1 := rand.NewSource(time.Now().UnixNano())
 	r1 := rand.New(s1)
 
+	var params ArkEnvParams
 	selectedPeer := ""
-	EnvironmentParams.Network.Type = arknetwork
 	//looping peers comunication until we get autoconfigure response
 	i := 0
 	for selectedPeer == "" && i < 10 {
@@ -93,34 +111,60 @@ func LoadActiveConfiguration(arknetwork ArkNetworkType) string {
 		if err != nil {
 			log.Println("Error receiving autoloader params rest from: ", selectedPeer, " Error: ", err.Error())
 			selectedPeer = ""
-		} else {
-			json.NewDecoder(res.Body).Decode(&EnvironmentParams)
+			continue
+		}
+		params = ArkEnvParams{}
+		err = json.NewDecoder(res.Body).Decode(&params)
+		res.Body.Close()
+		if err != nil || params.Network.Nethash == "" {
+			log.Println("Wrong autoloader params rest from: ", selectedPeer)
+			selectedPeer = ""
 		}
 	}
 
 	if selectedPeer == "" {
-		log.Fatal("Unable to connect to blockchain, exiting")
+		return "", ErrNoPeer
 	}
 
 	//reading fees
 	res, err := http.Get("http://" + selectedPeer + "/api/blocks/getfees")
 	if err != nil {
-		log.Fatal("Error receiving fees params rest from: ", selectedPeer)
+		return "", fmt.Errorf("error receiving fees params rest from %s: %s", selectedPeer, err.Error())
+	}
+	err = json.NewDecoder(res.Body).Decode(&params)
+	res.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("error reading fees params rest from %s: %s", selectedPeer, err.Error())
 	}
-	json.NewDecoder(res.Body).Decode(&EnvironmentParams)
 
 	//getting connected peer params from peer
 	peerParams := strings.Split(selectedPeer, ":")
 	peerRes := new(PeerResponse)
 	res, err = http.Get("http://" + selectedPeer + "/api/peers/get/?ip=" + peerParams[0] + "&port=" + peerParams[1])
 	if err != nil {
-		log.Fatal("Error receiving peer status from: ", selectedPeer, err.Error(), res.StatusCode)
+		return "", fmt.Errorf("error receiving peer status from %s: %s", selectedPeer, err.Error())
+	}
+	err = json.NewDecoder(res.Body).Decode(peerRes)
+	res.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("error reading peer status from %s: %s", selectedPeer, err.Error())
 	}
-	json.NewDecoder(res.Body).Decode(peerRes)
-	//saving peer parameters to globals
-	EnvironmentParams.Network.ActivePeer = peerRes.SinglePeer
+	//saving parameters to globals, Success marks loaded configuration
+	params.Success = true
+	params.Network.Type = arknetwork
+	params.Network.ActivePeer = peerRes.SinglePeer
+	params.Network.TransactionVersion = transactionVersion(peerRes.SinglePeer.Version)
+	*EnvironmentParams = params
 
-	return "http://" + optimizePeerList(selectedPeer)
+	return "http://" + optimizePeerList(selectedPeer), nil
+}
+
+//transactionVersion returns transaction serialization of peers with version peerVersion, AIP-11 since 2.0
+func transactionVersion(peerVersion string) byte {
+	if major, err := strconv.Atoi(strings.SplitN(peerVersion, ".", 2)[0]); err == nil && major >= 2 {
+		return TransactionV2
+	}
+	return TransactionV1
 }
 
 func optimizePeerList(selectedPeer string) string {
@@ -181,26 +225,49 @@ func optimizePeerList(selectedPeer string) string {
 	return selectedPeer
 }
 
-func switchNetwork(arkNetwork ArkNetworkType) {
-	BaseURL = LoadActiveConfiguration(arkNetwork)
-	var wifHeader = []byte{170}
+//switchNetwork loads network configuration and sets matching address parameters.
+//Without a responding peer the connection is left unchanged.
+func switchNetwork(arkNetwork ArkNetworkType) error {
+	baseURL, err := LoadActiveConfiguration(arkNetwork)
+	if err != nil {
+		return err
+	}
+	BaseURL = baseURL
+	baseParams := arkcoin.ArkCoinMain
 
 	if arkNetwork == DEVNET {
-		wifHeader = []byte{239}
+		baseParams = arkcoin.ArkCoinDevTest
 	}
 
 	coinParams := arkcoin.Params{
+		Name:                   baseParams.Name,
 		AddressHeader:          EnvironmentParams.Network.AddressVersion,
-		DumpedPrivateKeyHeader: wifHeader,
+		DumpedPrivateKeyHeader: baseParams.DumpedPrivateKeyHeader,
+		HDPrivateKeyID:         baseParams.HDPrivateKeyID,
+		HDPublicKeyID:          baseParams.HDPublicKeyID,
+		HDCoinType:             baseParams.HDCoinType,
 	}
 	arkcoin.SetActiveCoinConfiguration(&coinParams)
+	return nil
 }
 
 //SetActiveConfiguration sets a new client connection, switches network and reads network settings from peer
 //usage - must reassing new pointer value: arkapi = arkapi.SetActiveConfiguration(MAINNET)
+//If no peer responds, the error is logged and the client keeps the previous connection, see SwitchNetwork.
 func (s *ArkClient) SetActiveConfiguration(arkNetwork ArkNetworkType) *ArkClient {
-	switchNetwork(arkNetwork)
-	return NewArkClient(nil)
+	arkclient, err := s.SwitchNetwork(arkNetwork)
+	if err != nil {
+		log.Println(err.Error())
+	}
+	return arkclient
+}
+
+//SwitchNetwork is SetActiveConfiguration returning the error of loading network configuration.
+//On error the client of the previous configuration is returned. Without a loaded configuration
+//(EnvironmentParams.Success is false, e.g. no peer responded at init) clients must not be used.
+func (s *ArkClient) SwitchNetwork(arkNetwork ArkNetworkType) (*ArkClient, error) {
+	err := switchNetwork(arkNetwork)
+	return NewArkClient(nil), err
 }
 
 var seedList = [...]string{
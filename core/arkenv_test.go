@@ -2,6 +2,7 @@ package core
 
 import (
 	"log"
+	"reflect"
 	"testing"
 )
 
@@ -40,3 +41,16 @@ func TestGetConfigurationNative(t *testing.T) {
 	}
 
 }
+
+func TestSwitchNetworkKeepsConfiguration(t *testing.T) {
+	previous, baseURL := *EnvironmentParams, BaseURL
+	arkapi, err := NewArkClient(nil).SwitchNetwork(DEVNET)
+	if err == nil {
+		arkapi.SetActiveConfiguration(previous.Network.Type)
+		t.Skip("DEVNET peers responded")
+	}
+	log.Println(t.Name(), err)
+	if !reflect.DeepEqual(previous, *EnvironmentParams) || BaseURL != baseURL {
+		t.Error("Configuration changed by failed switch", EnvironmentParams.Network.Type, EnvironmentParams.Fees)
+	}
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//MaxFeeMultiplier limits fee overrides to this multiple of the network fee of the transaction type
+const MaxFeeMultiplier = 10
+
+var (
+	//ErrBuilderSigner is returned by Build when no signer is set
+	ErrBuilderSigner = errors.New("transaction signer is not set")
+	//ErrTransactionAmount is returned for missing or negative amount of transfers and locks, or amount of other types
+	ErrTransactionAmount = errors.New("transaction amount is invalid for its type")
+	//ErrSelfSend is returned when transfer or lock recipient is the sender
+	ErrSelfSend = errors.New("transaction recipient is the sender")
+	//ErrTransactionFee is returned for negative fees and fees over MaxFeeMultiplier times the network fee
+	ErrTransactionFee = fmt.Errorf("transaction fee is negative or more than %d times the network fee", MaxFeeMultiplier)
+	//ErrTransactionAsset is returned for missing or malformed asset of the transaction type
+	ErrTransactionAsset = errors.New("transaction asset is invalid")
+)
+
+//TransactionBuilder builds signed transactions of any type, validating them before anything is signed.
+//usage: tx, err := NewTransactionBuilder(SENDARK).Recipient(address).Amount(satoshi).Signer(signer, nil).Build()
+type TransactionBuilder struct {
+	tx           Transaction
+	feeOverride  bool
+	signer       Signer
+	secondSigner Signer
+}
+
+//NewTransactionBuilder returns builder of txType transaction
+func NewTransactionBuilder(txType byte) *TransactionBuilder {
+	return &TransactionBuilder{tx: Transaction{Type: txType, Asset: make(map[string]string)}}
+}
+
+//Recipient sets recipient address
+func (b *TransactionBuilder) Recipient(recipientID string) *TransactionBuilder {
+	b.tx.RecipientID = recipientID
+	return b
+}
+
+//Amount sets amount in satoshi
+func (b *TransactionBuilder) Amount(satoshiAmount int64) *TransactionBuilder {
+	b.tx.Amount = satoshiAmount
+	return b
+}
+
+//Fee overrides network fee of the transaction type, in satoshi
+func (b *TransactionBuilder) Fee(satoshiFee int64) *TransactionBuilder {
+	b.tx.Fee = satoshiFee
+	b.feeOverride = true
+	return b
+}
+
+//VendorField sets vendor field (smartbridge)
+func (b *TransactionBuilder) VendorField(vendorField string) *TransactionBuilder {
+	b.tx.VendorField = vendorField
+	return b
+}
+
+//Asset sets asset value, keys are the same as in transactions returned by Create functions
+func (b *TransactionBuilder) Asset(key, value string) *TransactionBuilder {
+	b.tx.Asset[key] = value
+	return b
+}
+
+//Payments adds multipayment payments
+func (b *TransactionBuilder) Payments(payments ...Payment) *TransactionBuilder {
+	b.tx.Payments = append(b.tx.Payments, payments...)
+	return b
+}
+
+//Signer sets signer and optional secondSigner (nil for accounts without second signature)
+func (b *TransactionBuilder) Signer(signer, secondSigner Signer) *TransactionBuilder {
+	b.signer, b.secondSigner = signer, secondSigner
+	return b
+}
+
+//Passphrase sets passphrase signers, secondPassphrase is optional
+func (b *TransactionBuilder) Passphrase(passphrase, secondPassphrase string) *TransactionBuilder {
+	return b.Signer(NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//Key sets private key signers, secondKey is optional
+func (b *TransactionBuilder) Key(key, secondKey *arkcoin.PrivateKey) *TransactionBuilder {
+	return b.Signer(NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//Build validates and signs the transaction. Each call returns a new transaction, with a new nonce on AIP-11 networks.
+func (b *TransactionBuilder) Build() (*Transaction, error) {
+	if b.signer == nil {
+		return nil, ErrBuilderSigner
+	}
+	tx := b.tx.template()
+
+	sender, err := arkcoin.NewPublicKey(b.signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.complete(sender, b.feeOverride); err != nil {
+		return nil, err
+	}
+	return tx.signAll(b.signer, b.secondSigner)
+}
+
+//complete validates unsigned transaction of sender and sets its defaults - vote recipient on legacy networks
+//and network fee unless feeOverride. Fee overrides are checked against MaxFeeMultiplier.
+func (tx *Transaction) complete(sender *arkcoin.PublicKey, feeOverride bool) error {
+	if tx.Type == VOTE && tx.RecipientID == "" && EnvironmentParams.Network.TransactionVersion < TransactionV2 {
+		tx.RecipientID = sender.Address()
+	}
+	if err := tx.validate(sender); err != nil {
+		return err
+	}
+
+	networkFee := tx.networkFee()
+	if !feeOverride {
+		tx.Fee = networkFee
+	} else if tx.Fee < 0 || networkFee > 0 && tx.Fee > networkFee*MaxFeeMultiplier {
+		return ErrTransactionFee
+	}
+	if len(tx.Asset) == 0 {
+		tx.Asset = nil
+	}
+	return nil
+}
+
+//template returns copy of unsigned transaction, asset and payments are copied too
+func (tx *Transaction) template() Transaction {
+	copied := *tx
+	copied.Asset = make(map[string]string, len(tx.Asset))
+	for key, value := range tx.Asset {
+		copied.Asset[key] = value
+	}
+	copied.Payments = append([]Payment(nil), tx.Payments...)
+	return copied
+}
+
+//networkFee returns network fee of the transaction type
+func (tx *Transaction) networkFee() int64 {
+	switch tx.Type {
+	case SENDARK:
+		return EnvironmentParams.Fees.Send
+	case SECONDSIGNATURE:
+		return EnvironmentParams.Fees.SecondSignature
+	case CREATEDELEGATE:
+		return EnvironmentParams.Fees.Delegate
+	case VOTE:
+		return EnvironmentParams.Fees.Vote
+	case MULTISIGNATURE:
+		keys, _, _, _ := tx.MultiSignatureAsset()
+		return EnvironmentParams.Fees.MultiSignature * int64(len(keys)+1)
+	case MULTIPAYMENT:
+		return MultiPaymentFee()
+	case HTLCLOCK:
+		if EnvironmentParams.Fees.HTLCLock > 0 {
+			return EnvironmentParams.Fees.HTLCLock
+		}
+		return EnvironmentParams.Fees.Send
+	}
+	return 0
+}
+
+//validate checks vendor field, amount, recipient and asset of unsigned transaction sent by sender
+func (tx *Transaction) validate(sender *arkcoin.PublicKey) error {
+	v2 := EnvironmentParams.Network.TransactionVersion >= TransactionV2
+	switch {
+	case v2 && tx.VendorField != "" && !hasVendorField(tx.Type):
+		return fmt.Errorf("type %d: %s", tx.Type, ErrTransactionFormat.Error())
+	case v2 && len(tx.VendorField) > MaxVendorFieldLengthV2:
+		return fmt.Errorf("vendor field is longer than %d bytes", MaxVendorFieldLengthV2)
+	case !v2 && len(tx.VendorField) > arkcoin.MaxVendorFieldLength:
+		return ErrVendorFieldTooLong
+	}
+
+	switch tx.Type {
+	case SENDARK, HTLCLOCK:
+		if tx.Amount <= 0 {
+			return ErrTransactionAmount
+		}
+		if err := arkcoin.ValidateAddress(tx.RecipientID, arkcoin.ActiveCoinConfig); err != nil {
+			return err
+		}
+		if tx.RecipientID == sender.Address() {
+			return ErrSelfSend
+		}
+	default:
+		if tx.Amount != 0 {
+			return ErrTransactionAmount
+		}
+	}
+
+	switch tx.Type {
+	case SENDARK:
+	case SECONDSIGNATURE:
+		key, err := hex.DecodeString(tx.Asset["signature"])
+		if err != nil || len(key) != 33 {
+			return ErrTransactionAsset
+		}
+		if _, err = arkcoin.NewPublicKey(key, arkcoin.ActiveCoinConfig); err != nil {
+			return ErrTransactionAsset
+		}
+	case CREATEDELEGATE:
+		if !isDelegateUsername(tx.Asset["username"]) {
+			return ErrTransactionAsset
+		}
+	case VOTE:
+		votes := tx.Asset["votes"]
+		if len(votes) == 0 || len(votes)%txVoteLength != 0 {
+			return ErrTransactionAsset
+		}
+		for ; len(votes) > 0; votes = votes[txVoteLength:] {
+			key, err := hex.DecodeString(votes[1:txVoteLength])
+			if votes[0] != '+' && votes[0] != '-' || err != nil {
+				return ErrTransactionAsset
+			}
+			if _, err = arkcoin.NewPublicKey(key, arkcoin.ActiveCoinConfig); err != nil {
+				return ErrTransactionAsset
+			}
+		}
+	case MULTISIGNATURE:
+		tx.SenderPublicKey = hex.EncodeToString(sender.Serialize())
+		//template gets its version when signed, AIP-11 registrations have no lifetime
+		asset := *tx
+		if v2 {
+			asset.Version = TransactionV2
+		}
+		keys, min, lifetime, err := asset.MultiSignatureAsset()
+		if err != nil {
+			return err
+		}
+		if min < 1 || min > len(keys) {
+			return ErrMultiSignatureMin
+		}
+		if !v2 && (lifetime < 1 || lifetime > MultiSignatureMaxLifetime) {
+			return ErrMultiSignatureLifetime
+		}
+	case MULTIPAYMENT:
+		if !v2 {
+			return ErrMultiPaymentVersion
+		}
+		if len(tx.Payments) < MultiPaymentMinPayments || len(tx.Payments) > MultiPaymentMaxPayments {
+			return ErrMultiPaymentCount
+		}
+		var total int64
+		for _, payment := range tx.Payments {
+			if payment.Amount <= 0 || total > 1<<63-1-payment.Amount {
+				return ErrMultiPaymentAmount
+			}
+			total += payment.Amount
+			if err := arkcoin.ValidateAddress(payment.RecipientID, arkcoin.ActiveCoinConfig); err != nil {
+				return fmt.Errorf("recipient %s: %s", payment.RecipientID, err.Error())
+			}
+			if payment.RecipientID == sender.Address() {
+				return ErrSelfSend
+			}
+		}
+	case HTLCLOCK, HTLCCLAIM, HTLCREFUND:
+		if !v2 {
+			return ErrHTLCVersion
+		}
+		if tx.Type == HTLCLOCK {
+			_, expiration, err := tx.HTLCLockAsset()
+			if err != nil {
+				return err
+			}
+			if expiration.Type == HTLCExpirationTimestamp && expiration.expired(0, GetTime()) {
+				return ErrHTLCExpiration
+			}
+			break
+		}
+		if lockID, err := hex.DecodeString(tx.Asset["lockTransactionId"]); err != nil || len(lockID) != 32 {
+			return ErrHTLCLockID
+		}
+		if secret, err := hex.DecodeString(tx.Asset["unlockSecret"]); tx.Type == HTLCCLAIM && (err != nil || len(secret) != HTLCSecretLength) {
+			return ErrHTLCSecret
+		}
+	default:
+		return ErrTransactionType
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"encoding/hex"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	tx, err := NewTransactionBuilder(SENDARK).Recipient(testRecipient()).Amount(100000000).VendorField("builder").
+		Passphrase("this is a top secret passphrase", "second passphrase").Build()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), tx.ToJSON())
+	if tx.Fee != EnvironmentParams.Fees.Send || tx.Asset != nil {
+		t.Error("Wrong transfer", tx.ToJSON())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = tx.SecondVerify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	tx, err = NewTransactionBuilder(SENDARK).Recipient(testRecipient()).Amount(1).Fee(EnvironmentParams.Fees.Send/2).
+		Passphrase("this is a top secret passphrase", "").Build()
+	if err != nil || tx.Fee != EnvironmentParams.Fees.Send/2 {
+		t.Error("Fee override not used", err)
+	}
+
+	delegateKey := hex.EncodeToString(NewPassphraseSigner("delegate").PublicKey())
+	vote, err := NewTransactionBuilder(VOTE).Asset("votes", "+"+delegateKey).Passphrase("this is a top secret passphrase", "").Build()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if vote.Fee != EnvironmentParams.Fees.Vote || vote.RecipientID == "" {
+		t.Error("Wrong vote", vote.ToJSON())
+	}
+	if err = vote.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestTransactionBuilderErrors(t *testing.T) {
+	builder := func(txType byte) *TransactionBuilder {
+		return NewTransactionBuilder(txType).Passphrase("this is a top secret passphrase", "")
+	}
+	senderKey := NewPassphraseSigner("this is a top secret passphrase")
+	self := senderKey.key.PublicKey.Address()
+	otherNetwork := "DFTzLwEHKKn3VGce6vZSueEmoPWpEZswhB"
+	if EnvironmentParams.Network.Type == DEVNET {
+		otherNetwork = "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25"
+	}
+
+	tests := []struct {
+		name    string
+		builder *TransactionBuilder
+		err     error
+	}{
+		{"no signer", NewTransactionBuilder(SENDARK).Recipient(testRecipient()).Amount(1), ErrBuilderSigner},
+		{"zero amount", builder(SENDARK).Recipient(testRecipient()), ErrTransactionAmount},
+		{"negative amount", builder(SENDARK).Recipient(testRecipient()).Amount(-1), ErrTransactionAmount},
+		{"vote amount", builder(VOTE).Amount(1).Asset("votes", "+"+hex.EncodeToString(senderKey.PublicKey())), ErrTransactionAmount},
+		{"self send", builder(SENDARK).Recipient(self).Amount(1), ErrSelfSend},
+		{"long vendor field", builder(SENDARK).Recipient(testRecipient()).Amount(1).VendorField(strings.Repeat("x", 65)), ErrVendorFieldTooLong},
+		{"negative fee", builder(SENDARK).Recipient(testRecipient()).Amount(1).Fee(-1), ErrTransactionFee},
+		{"fee too big", builder(SENDARK).Recipient(testRecipient()).Amount(1).Fee(EnvironmentParams.Fees.Send*MaxFeeMultiplier + 1), ErrTransactionFee},
+		{"unknown type", builder(5), ErrTransactionType},
+		{"missing vote", builder(VOTE), ErrTransactionAsset},
+		{"bad vote", builder(VOTE).Asset("votes", "*"+hex.EncodeToString(senderKey.PublicKey())), ErrTransactionAsset},
+		{"bad username", builder(CREATEDELEGATE).Asset("username", "Upper Case"), ErrTransactionAsset},
+		{"short second key", builder(SECONDSIGNATURE).Asset("signature", "02abcd"), ErrTransactionAsset},
+		{"legacy multipayment", builder(MULTIPAYMENT).Payments(Payment{1, testRecipient()}, Payment{2, testRecipient()}), ErrMultiPaymentVersion},
+		{"legacy HTLC", builder(HTLCREFUND).Asset("lockTransactionId", hex.EncodeToString(make([]byte, 32))), ErrHTLCVersion},
+	}
+	for _, test := range tests {
+		if _, err := test.builder.Build(); err != test.err {
+			t.Error(test.name, "wrong error", err)
+		}
+	}
+
+	if _, err := builder(SENDARK).Recipient(otherNetwork).Amount(1).Build(); err == nil {
+		t.Error("Recipient of other network accepted")
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	tx, err := CreateTransaction(testRecipient(), 1, "", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx.SenderPublicKey = "not hex"
+	if err := tx.Verify(); err == nil {
+		t.Error("Malformed sender public key verified")
+	}
+	tx.SecondSenderPublicKey = "02abcd"
+	if err := tx.SecondVerify(); err == nil {
+		t.Error("Malformed second public key verified")
+	}
+	tx.VendorField = strings.Repeat("x", 65)
+	if _, err := tx.toBytes(true, true); err == nil {
+		t.Error("Long vendor field serialized")
+	}
+}
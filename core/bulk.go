@@ -0,0 +1,97 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//BulkResult is signed transaction or error of the template with the same index
+type BulkResult struct {
+	Transaction *Transaction
+	Err         error
+	//Prepared is true when the template got its timestamp and nonce, an error of prepared template
+	//leaves a nonce gap on AIP-11 networks
+	Prepared bool
+}
+
+//BulkProgress is called after each signed template with the number of done templates
+type BulkProgress func(done, total int)
+
+//SignBulk validates and signs transaction templates with signer and optional secondSigner on workers goroutines
+//(NumCPU for workers < 1). Templates are filled in like in TransactionBuilder - zero fee means network fee of
+//the type. Results are in order of templates, invalid templates get their error and are not signed.
+//progress (can be nil) is called from the calling goroutine. Templates are not modified.
+//
+//On AIP-11 networks nonces are handed out in order of valid templates. A signing error (of a Prepared
+//result) leaves a gap, so the transactions after it should not be sent and Nonces should be reset.
+func SignBulk(templates []Transaction, signer, secondSigner Signer, workers int, progress BulkProgress) []BulkResult {
+	results := make([]BulkResult, len(templates))
+	sender, err := arkcoin.NewPublicKey(signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	//validation and nonces are sequential, signing is the expensive part
+	var pending []int
+	for i := range templates {
+		tx := templates[i].template()
+		if err := tx.complete(sender, tx.Fee != 0); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := tx.prepareSign(signer.PublicKey()); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Transaction, results[i].Prepared = &tx, true
+		pending = append(pending, i)
+	}
+
+	done := len(templates) - len(pending)
+	if progress != nil && done > 0 {
+		progress(done, len(templates))
+	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	jobs := make(chan int)
+	signed := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := results[i].Transaction.signPrepared(signer, secondSigner); err != nil {
+					results[i] = BulkResult{Err: err, Prepared: true}
+				}
+				signed <- i
+			}
+		}()
+	}
+	go func() {
+		for _, i := range pending {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(signed)
+	}()
+
+	for range signed {
+		done++
+		if progress != nil {
+			progress(done, len(templates))
+		}
+	}
+	return results
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+)
+
+func bulkTemplates(n int) []Transaction {
+	templates := make([]Transaction, n)
+	for i := range templates {
+		templates[i] = Transaction{Type: SENDARK, RecipientID: testRecipient(), Amount: int64(i + 1), VendorField: "bulk payout"}
+	}
+	return templates
+}
+
+func TestSignBulk(t *testing.T) {
+	templates := bulkTemplates(100)
+	templates[10].RecipientID = "not an address"
+	templates[20].Amount = 0
+	templates[30].Fee = EnvironmentParams.Fees.Send / 2
+
+	lastDone, calls := 0, 0
+	results := SignBulk(templates, NewPassphraseSigner("this is a top secret passphrase"), NewPassphraseSigner("second passphrase"), 4, func(done, total int) {
+		if done < lastDone || total != len(templates) {
+			t.Error("Wrong progress", done, total)
+		}
+		lastDone = done
+		calls++
+	})
+	if lastDone != len(templates) || calls == 0 {
+		t.Error("Progress not finished", lastDone, calls)
+	}
+	if templates[0].Signature != "" || templates[0].Fee != 0 {
+		t.Error("Template modified")
+	}
+
+	for i, result := range results {
+		switch i {
+		case 10:
+			if result.Err == nil {
+				t.Error("Invalid recipient signed")
+			}
+			continue
+		case 20:
+			if result.Err != ErrTransactionAmount {
+				t.Error("Zero amount signed", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Error(i, result.Err.Error())
+			continue
+		}
+		tx := result.Transaction
+		if tx.Amount != int64(i+1) {
+			t.Error("Order not preserved", i, tx.Amount)
+		}
+		if i == 30 && tx.Fee != EnvironmentParams.Fees.Send/2 || i != 30 && tx.Fee != EnvironmentParams.Fees.Send {
+			t.Error("Wrong fee", i, tx.Fee)
+		}
+		if err := tx.Verify(); err != nil {
+			t.Error(i, err.Error())
+		}
+		if err := tx.SecondVerify(); err != nil {
+			t.Error(i, err.Error())
+		}
+	}
+}
+
+func TestSignBulkV2Nonces(t *testing.T) {
+	defer useV2Network(t, "5")()
+
+	templates := bulkTemplates(20)
+	templates[3].Amount = -1
+	results := SignBulk(templates, NewPassphraseSigner("this is a top secret passphrase"), nil, 0, nil)
+
+	nonce := uint64(6)
+	for i, result := range results {
+		if i == 3 {
+			if result.Err == nil {
+				t.Error("Negative amount signed")
+			}
+			continue
+		}
+		if result.Err != nil || result.Transaction.Nonce != nonce {
+			t.Error("Wrong nonce", i, result.Err, result.Transaction)
+			continue
+		}
+		if err := result.Transaction.Verify(); err != nil {
+			t.Error(i, err.Error())
+		}
+		nonce++
+	}
+}
+
+func BenchmarkSignBulk(b *testing.B) {
+	templates := bulkTemplates(2000)
+	signer, secondSigner := NewPassphraseSigner("this is a top secret passphrase"), NewPassphraseSigner("second passphrase")
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		SignBulk(templates, signer, secondSigner, 0, nil)
+	}
+}
+
+func BenchmarkCreateTransactionSequential(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 2000; i++ {
+			CreateTransaction(testRecipient(), int64(i+1), "bulk payout", "this is a top secret passphrase", "second passphrase")
+		}
+	}
+}
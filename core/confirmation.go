@@ -0,0 +1,273 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+//ConfirmationStatus is the state of a tracked transaction
+type ConfirmationStatus int
+
+//Statuses of tracked transactions. Confirmed, Dropped and Expired are final.
+const (
+	StatusPending     ConfirmationStatus = iota //not seen by any peer yet
+	StatusUnconfirmed                           //in transaction pool, or in a block with less confirmations than the depth
+	StatusConfirmed                             //in a block with at least depth confirmations
+	StatusDropped                               //was seen, then no peer knows it anymore
+	StatusExpired                               //not confirmed when the context was done
+)
+
+//DefaultConfirmationInterval is the polling interval of new trackers, the block time
+const DefaultConfirmationInterval = 8 * time.Second
+
+//droppedAfter is the number of rounds a seen transaction must be missing on all peers to be dropped,
+//a transaction moving from pool to block between the two queries is missing for one round
+const droppedAfter = 2
+
+func (s ConfirmationStatus) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusUnconfirmed:
+		return "unconfirmed"
+	case StatusConfirmed:
+		return "confirmed"
+	case StatusDropped:
+		return "dropped"
+	case StatusExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+//MarshalText returns status name, so reports are readable as JSON
+func (s ConfirmationStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+//TransactionStatus is the last known state of tracked transaction
+type TransactionStatus struct {
+	ID            string             `json:"id"`
+	Status        ConfirmationStatus `json:"status"`
+	Height        int                `json:"height,omitempty"` //block height, when in a block
+	Confirmations int                `json:"confirmations,omitempty"`
+	Peer          string             `json:"peer,omitempty"` //peer that reported the status
+	Rebroadcasts  int                `json:"rebroadcasts,omitempty"`
+	Updated       time.Time          `json:"updated"`
+	missing       int
+}
+
+//Final reports whether the status will not change anymore
+func (s TransactionStatus) Final() bool {
+	return s.Status == StatusConfirmed || s.Status == StatusDropped || s.Status == StatusExpired
+}
+
+func (s TransactionStatus) String() string {
+	if s.Status == StatusConfirmed || s.Height > 0 {
+		return fmt.Sprintf("%s %s at height %d (%d confirmations)", s.ID, s.Status, s.Height, s.Confirmations)
+	}
+	return s.ID + " " + s.Status.String()
+}
+
+//ConfirmationReport is the final state of transactions tracked by WaitForConfirmation
+type ConfirmationReport struct {
+	Depth        int                 `json:"depth"`
+	Started      time.Time           `json:"started"`
+	Finished     time.Time           `json:"finished"`
+	Transactions []TransactionStatus `json:"transactions"` //in order of tracked ids
+}
+
+//Count returns the number of transactions with status
+func (r ConfirmationReport) Count(status ConfirmationStatus) int {
+	count := 0
+	for _, tx := range r.Transactions {
+		if tx.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+//Confirmed reports whether all transactions are confirmed
+func (r ConfirmationReport) Confirmed() bool {
+	return r.Count(StatusConfirmed) == len(r.Transactions)
+}
+
+func (r ConfirmationReport) String() string {
+	return fmt.Sprintf("%d transactions: %d confirmed, %d unconfirmed, %d pending, %d dropped, %d expired", len(r.Transactions),
+		r.Count(StatusConfirmed), r.Count(StatusUnconfirmed), r.Count(StatusPending), r.Count(StatusDropped), r.Count(StatusExpired))
+}
+
+//ConfirmationTracker polls peers until transactions reach the confirmation depth
+type ConfirmationTracker struct {
+	Depth    int
+	Interval time.Duration
+	//OnStatus is called when status of a transaction changes, from the goroutine calling Wait
+	OnStatus func(TransactionStatus)
+	clients  []*ArkClient
+	names    []string
+	//dropped is called for dropped transaction ix, tracking continues when it returns true
+	dropped func(ctx context.Context, ix int, status *TransactionStatus) bool
+}
+
+//NewConfirmationTracker returns tracker of depth confirmations asking peers in turns. Without peers
+//the active peer is asked.
+func NewConfirmationTracker(depth int, peers ...Peer) *ConfirmationTracker {
+	tracker := &ConfirmationTracker{Depth: depth, Interval: DefaultConfirmationInterval}
+	if len(peers) == 0 {
+		tracker.clients, tracker.names = []*ArkClient{NewArkClient(nil)}, []string{BaseURL}
+		return tracker
+	}
+	for _, peer := range peers {
+		tracker.clients = append(tracker.clients, newPeerClient(peer))
+		tracker.names = append(tracker.names, peerURL(peer))
+	}
+	return tracker
+}
+
+//newPeerClient returns client of peer, unlike NewArkClientFromPeer the active peer is not changed
+func newPeerClient(peer Peer) *ArkClient {
+	return &ArkClient{
+		sling: sling.New().Base(peerURL(peer)).
+			Add("nethash", EnvironmentParams.Network.Nethash).
+			Add("version", peer.Version).
+			Add("port", strconv.Itoa(peer.Port)).
+			Add("Content-Type", "application/json"),
+	}
+}
+
+func peerURL(peer Peer) string {
+	return "http://" + peer.IP + ":" + strconv.Itoa(peer.Port)
+}
+
+//WaitForConfirmation waits for depth confirmations of transactions ids, asking up to three random peers
+//of the active network. See ConfirmationTracker.Wait.
+func WaitForConfirmation(ctx context.Context, ids []string, depth int) (ConfirmationReport, error) {
+	var peers []Peer
+	if len(EnvironmentParams.Network.PeerList) > 0 {
+		peers = NewArkClient(nil).GetRandomXPeers(3)
+	}
+	return NewConfirmationTracker(depth, peers...).Wait(ctx, ids)
+}
+
+//Wait polls peers until all transactions ids have a final status or ctx is done. Transactions not
+//final when ctx is done are expired and ctx error is returned with the report.
+func (t *ConfirmationTracker) Wait(ctx context.Context, ids []string) (ConfirmationReport, error) {
+	report := ConfirmationReport{Depth: t.Depth, Started: time.Now()}
+	for _, id := range ids {
+		report.Transactions = append(report.Transactions, TransactionStatus{ID: id, Status: StatusPending, Updated: report.Started})
+	}
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultConfirmationInterval
+	}
+
+	for round := 0; ; round++ {
+		final := true
+		for i := range report.Transactions {
+			if ctx.Err() != nil {
+				break
+			}
+			tx := &report.Transactions[i]
+			if tx.Final() {
+				continue
+			}
+			t.update(ctx, tx, round+i)
+			if tx.Status == StatusDropped && t.dropped != nil && t.dropped(ctx, i, tx) {
+				t.set(tx, StatusPending, 0, 0, "")
+			}
+			final = final && tx.Final()
+		}
+		if final && ctx.Err() == nil {
+			report.Finished = time.Now()
+			return report, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for i, tx := range report.Transactions {
+				if !tx.Final() {
+					tx.Status, tx.Updated = StatusExpired, time.Now()
+					report.Transactions[i] = tx
+					t.notify(tx)
+				}
+			}
+			report.Finished = time.Now()
+			return report, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+//update asks peers for transaction status, starting with peer of turn. Unreachable peers are skipped,
+//status is kept when no peer answered.
+func (t *ConfirmationTracker) update(ctx context.Context, tx *TransactionStatus, turn int) {
+	answered, inPool := false, ""
+	for i := range t.clients {
+		ix := (turn + i) % len(t.clients)
+		block, found, ok := getTransaction(ctx, t.clients[ix], "api/transactions/get", tx.ID)
+		if !ok {
+			continue
+		}
+		answered = true
+		if found {
+			status := StatusUnconfirmed
+			if block.Confirmations >= t.Depth {
+				status = StatusConfirmed
+			}
+			t.set(tx, status, block.Height, block.Confirmations, t.names[ix])
+			return
+		}
+		if inPool == "" {
+			if _, found, _ = getTransaction(ctx, t.clients[ix], "api/transactions/unconfirmed/get", tx.ID); found {
+				inPool = t.names[ix]
+			}
+		}
+	}
+
+	switch {
+	case inPool != "":
+		t.set(tx, StatusUnconfirmed, 0, 0, inPool)
+	case answered && tx.Status != StatusPending:
+		if tx.missing++; tx.missing >= droppedAfter {
+			t.set(tx, StatusDropped, tx.Height, tx.Confirmations, "")
+		}
+	}
+}
+
+//getTransaction gets transaction id from path of client peer - confirmed or unconfirmed transactions.
+//ok is false when the peer did not answer with a 2xx response and a parsed body (errors, rate limits and
+//peers without the route are not answers), requests are cancelled with ctx.
+func getTransaction(ctx context.Context, client *ArkClient, path, id string) (tx Transaction, found, ok bool) {
+	req, err := client.sling.New().Get(path).QueryStruct(&TransactionQueryParams{ID: id}).Request()
+	if err != nil {
+		return tx, false, false
+	}
+	transactionResponse := new(TransactionResponse)
+	resp, err := client.sling.Do(req.WithContext(ctx), transactionResponse, new(ArkApiResponseError))
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return tx, false, false
+	}
+	found = transactionResponse.Success && transactionResponse.SingleTransaction.ID == id
+	return transactionResponse.SingleTransaction, found, true
+}
+
+//set changes status and notifies about changes
+func (t *ConfirmationTracker) set(tx *TransactionStatus, status ConfirmationStatus, height, confirmations int, peer string) {
+	tx.missing = 0
+	changed := tx.Status != status || tx.Height != height
+	tx.Status, tx.Height, tx.Confirmations, tx.Peer, tx.Updated = status, height, confirmations, peer, time.Now()
+	if changed {
+		t.notify(*tx)
+	}
+}
+
+func (t *ConfirmationTracker) notify(tx TransactionStatus) {
+	if t.OnStatus != nil {
+		t.OnStatus(tx)
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+//confirmationPeer returns peer of test server, A gets a confirmation per request, B is in the pool
+//for two requests and is dropped then, other transactions are unknown
+func confirmationPeer() (Peer, func()) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := r.URL.Query().Get("id")
+		requests[r.URL.Path+id]++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/transactions/get" && id == "A":
+			w.Write([]byte(`{"success":true,"transaction":{"id":"A","type":0,"height":100,"confirmations":` + strconv.Itoa(requests[r.URL.Path+id]) + `}}`))
+		case r.URL.Path == "/api/transactions/unconfirmed/get" && id == "B" && requests[r.URL.Path+id] <= 2:
+			w.Write([]byte(`{"success":true,"transaction":{"id":"B","type":0}}`))
+		default:
+			w.Write([]byte(`{"success":false,"error":"Transaction not found"}`))
+		}
+	}))
+	address, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(address.Port())
+	return Peer{IP: address.Hostname(), Port: port}, server.Close
+}
+
+func TestWaitForConfirmation(t *testing.T) {
+	peer, closePeer := confirmationPeer()
+	defer closePeer()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableAddress, _ := url.Parse(unreachable.URL)
+	unreachable.Close()
+	unreachablePort, _ := strconv.Atoi(unreachableAddress.Port())
+
+	tracker := NewConfirmationTracker(3, Peer{IP: unreachableAddress.Hostname(), Port: unreachablePort}, peer)
+	tracker.Interval = 10 * time.Millisecond
+	var changes []TransactionStatus
+	tracker.OnStatus = func(status TransactionStatus) {
+		changes = append(changes, status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	report, err := tracker.Wait(ctx, []string{"A", "B", "C"})
+	if err != context.DeadlineExceeded {
+		t.Error("Deadline not reported", err)
+	}
+	log.Println(t.Name(), report)
+
+	expected := []ConfirmationStatus{StatusConfirmed, StatusDropped, StatusExpired}
+	for i, status := range report.Transactions {
+		if status.Status != expected[i] {
+			t.Error("Wrong status", status)
+		}
+	}
+	if a := report.Transactions[0]; a.Height != 100 || a.Confirmations != 3 || a.Peer != "http://"+peer.IP+":"+strconv.Itoa(peer.Port) {
+		t.Error("Wrong confirmation", a)
+	}
+	if len(changes) == 0 || changes[len(changes)-1].ID != "C" || changes[len(changes)-1].Status != StatusExpired {
+		t.Error("Wrong status changes", changes)
+	}
+	if report.Confirmed() || report.Count(StatusConfirmed) != 1 {
+		t.Error("Wrong report counts", report)
+	}
+}
+
+func TestWaitForConfirmationPeerErrors(t *testing.T) {
+	var mu sync.Mutex
+	poolRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case poolRequests >= 2:
+		case r.URL.Path == "/api/transactions/get":
+			w.Write([]byte(`{"success":false,"error":"Transaction not found"}`))
+			return
+		case r.URL.Path == "/api/transactions/unconfirmed/get":
+			poolRequests++
+			w.Write([]byte(`{"success":true,"transaction":{"id":"B","type":0}}`))
+			return
+		}
+		//B was in the pool, the peer fails then
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"success":false,"error":"Internal error"}`))
+	}))
+	defer server.Close()
+	address, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(address.Port())
+
+	tracker := NewConfirmationTracker(1, Peer{IP: address.Hostname(), Port: port})
+	tracker.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	report, _ := tracker.Wait(ctx, []string{"B"})
+	if status := report.Transactions[0]; status.Status != StatusExpired {
+		t.Error("Failing peer answer counted", status)
+	}
+}
+
+func TestWaitForConfirmationDone(t *testing.T) {
+	peer, closePeer := confirmationPeer()
+	defer closePeer()
+
+	tracker := NewConfirmationTracker(2, peer)
+	tracker.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := tracker.Wait(ctx, []string{"A"})
+	if err != nil || !report.Confirmed() || report.Finished.Before(report.Started) {
+		t.Error("Transaction not confirmed", err, report)
+	}
+}
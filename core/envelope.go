@@ -0,0 +1,344 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//EnvelopeVersion is the version of envelopes created by this package
+const EnvelopeVersion = 1
+
+var (
+	//ErrEnvelopeVersion is returned for envelopes of unknown version
+	ErrEnvelopeVersion = errors.New("unknown envelope version")
+	//ErrEnvelopeChecksum is returned when envelope content does not match its checksum
+	ErrEnvelopeChecksum = errors.New("envelope checksum mismatch, envelope is corrupted or was modified")
+	//ErrEnvelopeNetwork is returned when envelope nethash is not the active network nethash
+	ErrEnvelopeNetwork = errors.New("envelope was created for another network")
+	//ErrEnvelopeNoNetwork is returned when envelope is created or verified without loaded network configuration
+	ErrEnvelopeNoNetwork = errors.New("network configuration is not loaded")
+	//ErrEnvelopeSigner is returned when signer is not the sender of envelope transactions
+	ErrEnvelopeSigner = errors.New("signer is not the sender of envelope transactions")
+	//ErrEnvelopeUnsigned is returned when envelope transactions are missing the first signature
+	ErrEnvelopeUnsigned = errors.New("envelope transaction is not signed")
+	//ErrEnvelopeSummary is returned when envelope summary does not describe envelope transactions
+	ErrEnvelopeSummary = errors.New("envelope summary does not match its transactions")
+	//ErrEnvelopeMismatch is returned when merged envelopes do not hold the same transactions
+	ErrEnvelopeMismatch = errors.New("envelopes hold different transactions")
+	//ErrEnvelopeConflict is returned when merged envelopes hold different signatures of the same transaction
+	ErrEnvelopeConflict = errors.New("envelopes hold conflicting signatures")
+)
+
+//Envelope carries transactions between an online machine, which creates them unsigned with current fees,
+//timestamps and nonces, and an offline machine holding the keys. The online machine verifies the signed
+//envelope and broadcasts its transactions.
+//Checksum detects corrupted files only, anyone editing the file can recompute it. Summary is rebuilt from
+//the transactions when the envelope is parsed, so the summary shown to the signer is the summary of what gets signed.
+type Envelope struct {
+	Version      int            `json:"version"`
+	Nethash      string         `json:"nethash"`
+	Created      time.Time      `json:"created"`
+	Summary      []string       `json:"summary"`
+	Transactions []*Transaction `json:"transactions"`
+	Checksum     string         `json:"checksum"`
+}
+
+//NewEnvelope validates transaction templates of sender with hex senderPublicKey and returns envelope of
+//unsigned transactions. Templates are filled in like in TransactionBuilder - zero fee means network fee of
+//the type. On AIP-11 networks the next sender nonces are used, so the envelope must be created online.
+func NewEnvelope(senderPublicKey string, templates []Transaction) (*Envelope, error) {
+	if EnvironmentParams.Network.Nethash == "" {
+		return nil, ErrEnvelopeNoNetwork
+	}
+	keyBytes, err := hex.DecodeString(senderPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := arkcoin.NewPublicKey(keyBytes, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	//everything is validated before any nonce is used
+	transactions := make([]*Transaction, len(templates))
+	for i := range templates {
+		tx := templates[i].template()
+		if err = tx.complete(sender, tx.Fee != 0); err != nil {
+			return nil, fmt.Errorf("transaction %d: %s", i, err.Error())
+		}
+		tx.SenderPublicKey = senderPublicKey
+		transactions[i] = &tx
+	}
+	for _, tx := range transactions {
+		if err = tx.prepareSign(keyBytes); err != nil {
+			Nonces.Reset(senderPublicKey)
+			return nil, err
+		}
+	}
+
+	envelope := &Envelope{
+		Version:      EnvelopeVersion,
+		Nethash:      EnvironmentParams.Network.Nethash,
+		Created:      time.Now().UTC(),
+		Transactions: transactions,
+	}
+	return envelope, envelope.seal()
+}
+
+//ParseEnvelope parses JSON envelope and checks its version, checksum and summary
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	envelope := new(Envelope)
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Version != EnvelopeVersion {
+		return nil, ErrEnvelopeVersion
+	}
+	checksum, err := envelope.checksum()
+	if err != nil {
+		return nil, err
+	}
+	if checksum != envelope.Checksum {
+		return nil, ErrEnvelopeChecksum
+	}
+	summary := envelope.summary()
+	if len(summary) != len(envelope.Summary) {
+		return nil, ErrEnvelopeSummary
+	}
+	for i := range summary {
+		if summary[i] != envelope.Summary[i] {
+			return nil, ErrEnvelopeSummary
+		}
+	}
+	return envelope, nil
+}
+
+//ToJSON returns indented JSON of the envelope
+func (e *Envelope) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+//Sign adds signatures of signer and optional secondSigner to all transactions.
+//Offline machines do not know the nethash, it is checked only when the network configuration is loaded.
+func (e *Envelope) Sign(signer, secondSigner Signer) error {
+	if err := e.checkNetwork(); err != nil {
+		return err
+	}
+	senderPublicKey := hex.EncodeToString(signer.PublicKey())
+	for _, tx := range e.Transactions {
+		if tx.SenderPublicKey != senderPublicKey {
+			return ErrEnvelopeSigner
+		}
+	}
+	for _, tx := range e.Transactions {
+		if err := tx.signPrepared(signer, secondSigner); err != nil {
+			return err
+		}
+	}
+	return e.seal()
+}
+
+//SecondSign adds second signatures of secondSigner to transactions signed already, when the second secret
+//is kept on another machine than the first one
+func (e *Envelope) SecondSign(secondSigner Signer) error {
+	if err := e.checkNetwork(); err != nil {
+		return err
+	}
+	for _, tx := range e.Transactions {
+		if tx.Signature == "" {
+			return ErrEnvelopeUnsigned
+		}
+	}
+	for _, tx := range e.Transactions {
+		if err := tx.secondSign(secondSigner); err != nil {
+			return err
+		}
+		if err := tx.getID(); err != nil {
+			return err
+		}
+	}
+	return e.seal()
+}
+
+//Merge adds signatures of other envelope with the same transactions, e.g. multisignature keysgroup
+//signatures collected on several machines
+func (e *Envelope) Merge(other *Envelope) error {
+	if e.Nethash != other.Nethash || len(e.Transactions) != len(other.Transactions) {
+		return ErrEnvelopeMismatch
+	}
+	for i, tx := range e.Transactions {
+		if err := tx.merge(other.Transactions[i]); err != nil {
+			return fmt.Errorf("transaction %d: %s", i, err.Error())
+		}
+	}
+	return e.seal()
+}
+
+//Verify checks checksum, network and signatures of all transactions, the envelope can be broadcasted then
+func (e *Envelope) Verify() error {
+	checksum, err := e.checksum()
+	if err != nil {
+		return err
+	}
+	if checksum != e.Checksum {
+		return ErrEnvelopeChecksum
+	}
+	if EnvironmentParams.Network.Nethash == "" {
+		return ErrEnvelopeNoNetwork
+	}
+	if e.Nethash != EnvironmentParams.Network.Nethash {
+		return ErrEnvelopeNetwork
+	}
+
+	for i, tx := range e.Transactions {
+		if tx.Signature == "" {
+			return fmt.Errorf("transaction %d: %s", i, ErrEnvelopeUnsigned.Error())
+		}
+		if err = tx.Verify(); err != nil {
+			return fmt.Errorf("transaction %d: %s", i, err.Error())
+		}
+		if tx.SignSignature != "" {
+			if err = tx.SecondVerify(); err != nil {
+				return fmt.Errorf("transaction %d: %s", i, err.Error())
+			}
+		}
+		check := *tx
+		if err = check.getID(); err != nil || check.ID != tx.ID {
+			return fmt.Errorf("transaction %d: wrong id %s", i, tx.ID)
+		}
+	}
+	return nil
+}
+
+//Payload returns envelope transactions for PostTransaction
+func (e *Envelope) Payload() TransactionPayload {
+	return TransactionPayload{Transactions: e.Transactions}
+}
+
+//checkNetwork checks envelope nethash against the loaded network configuration, envelopes without
+//nethash are rejected also offline
+func (e *Envelope) checkNetwork() error {
+	if e.Nethash == "" || EnvironmentParams.Network.Nethash != "" && e.Nethash != EnvironmentParams.Network.Nethash {
+		return ErrEnvelopeNetwork
+	}
+	return nil
+}
+
+//summary returns human readable description of envelope transactions
+func (e *Envelope) summary() []string {
+	var total, fees int64
+	summary := make([]string, 0, len(e.Transactions)+1)
+	for i, tx := range e.Transactions {
+		total += tx.TotalAmount()
+		fees += tx.Fee
+		summary = append(summary, fmt.Sprintf("%d: %s", i+1, tx.summary()))
+	}
+	return append(summary, fmt.Sprintf("%d transactions, amount %s, fees %s", len(e.Transactions), formatArkAmount(total), formatArkAmount(fees)))
+}
+
+//seal updates summary and checksum after transactions changed
+func (e *Envelope) seal() error {
+	e.Summary = e.summary()
+	checksum, err := e.checksum()
+	if err != nil {
+		return err
+	}
+	e.Checksum = checksum
+	return nil
+}
+
+//checksum returns hex sha256 of envelope JSON without the checksum
+func (e *Envelope) checksum() (string, error) {
+	content := *e
+	content.Checksum = ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+//merge copies signatures of the same transaction other, missing in tx
+func (tx *Transaction) merge(other *Transaction) error {
+	unsigned, err := tx.toBytes(true, true)
+	if err != nil {
+		return err
+	}
+	otherUnsigned, err := other.toBytes(true, true)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(unsigned, otherUnsigned) {
+		return ErrEnvelopeMismatch
+	}
+
+	for _, field := range []struct{ value, otherValue *string }{
+		{&tx.Signature, &other.Signature},
+		{&tx.SecondSenderPublicKey, &other.SecondSenderPublicKey},
+		{&tx.SignSignature, &other.SignSignature},
+	} {
+		if *field.value != "" && *field.otherValue != "" && *field.value != *field.otherValue {
+			return ErrEnvelopeConflict
+		}
+		if *field.value == "" {
+			*field.value = *field.otherValue
+		}
+	}
+	for _, signature := range other.Signatures {
+		known := false
+		for _, own := range tx.Signatures {
+			known = known || own == signature
+		}
+		if !known {
+			tx.Signatures = append(tx.Signatures, signature)
+		}
+	}
+	if tx.Signature != "" {
+		return tx.getID()
+	}
+	return nil
+}
+
+//summary returns human readable description of transaction
+func (tx *Transaction) summary() string {
+	var text string
+	switch tx.Type {
+	case SENDARK:
+		text = fmt.Sprintf("transfer %s to %s", formatArkAmount(tx.Amount), tx.RecipientID)
+	case SECONDSIGNATURE:
+		text = "second signature registration " + tx.Asset["signature"]
+	case CREATEDELEGATE:
+		text = "delegate registration " + tx.Asset["username"]
+	case VOTE:
+		text = "vote " + tx.Asset["votes"]
+	case MULTISIGNATURE:
+		text = fmt.Sprintf("multisignature registration min %s of %s", tx.Asset["min"], tx.Asset["keysgroup"])
+	case MULTIPAYMENT:
+		text = fmt.Sprintf("multipayment %s to %d recipients", formatArkAmount(tx.TotalAmount()), len(tx.Payments))
+	case HTLCLOCK:
+		text = fmt.Sprintf("HTLC lock %s to %s", formatArkAmount(tx.Amount), tx.RecipientID)
+	case HTLCCLAIM:
+		text = "HTLC claim of " + tx.Asset["lockTransactionId"]
+	case HTLCREFUND:
+		text = "HTLC refund of " + tx.Asset["lockTransactionId"]
+	default:
+		text = fmt.Sprintf("type %d", tx.Type)
+	}
+	text += ", fee " + formatArkAmount(tx.Fee)
+	if tx.VendorField != "" {
+		text += ", vendor field " + strconv.Quote(tx.VendorField)
+	}
+	if tx.Version >= TransactionV2 {
+		text += ", nonce " + strconv.FormatUint(tx.Nonce, 10)
+	}
+	return text
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"log"
+	"strings"
+	"testing"
+)
+
+func useNethash(nethash string) func() {
+	previous := EnvironmentParams.Network.Nethash
+	EnvironmentParams.Network.Nethash = nethash
+	return func() {
+		EnvironmentParams.Network.Nethash = previous
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	defer useNethash("6e84d08bd299ed97c212c886c98a57e36545c8f5d645ca7eeae63a8bd62d8988")()
+
+	signer, secondSigner := NewPassphraseSigner("this is a top secret passphrase"), NewPassphraseSigner("second passphrase")
+	templates := []Transaction{
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 100000000, VendorField: "offline"},
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 250000000, Fee: EnvironmentParams.Fees.Send * 2},
+	}
+	envelope, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), templates)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if envelope.Transactions[0].Fee != EnvironmentParams.Fees.Send || envelope.Transactions[0].Signature != "" || len(envelope.Summary) != 3 {
+		t.Error("Wrong unsigned envelope", envelope.Summary)
+	}
+	if err = envelope.Verify(); err == nil {
+		t.Error("Unsigned envelope verified")
+	}
+
+	//offline machine holds the first secret, another one the second
+	data, _ := envelope.ToJSON()
+	offline, err := ParseEnvelope(data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = offline.Sign(NewPassphraseSigner("someone else"), nil); err != ErrEnvelopeSigner {
+		t.Error("Envelope signed by other sender", err)
+	}
+	if err = offline.SecondSign(secondSigner); err != ErrEnvelopeUnsigned {
+		t.Error("Unsigned envelope second signed", err)
+	}
+	if err = offline.Sign(signer, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	data, _ = offline.ToJSON()
+	second, _ := ParseEnvelope(data)
+	if err = second.SecondSign(secondSigner); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err = offline.Merge(second); err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), offline.Summary)
+	if err = offline.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	for _, tx := range offline.Transactions {
+		if tx.SignSignature == "" || tx.SecondVerify() != nil {
+			t.Error("Second signature not merged", tx.ToJSON())
+		}
+	}
+
+	other, _ := NewEnvelope(hex.EncodeToString(signer.PublicKey()), templates[:1])
+	if err = offline.Merge(other); err != ErrEnvelopeMismatch {
+		t.Error("Different envelopes merged", err)
+	}
+
+	data, _ = offline.ToJSON()
+	if _, err = ParseEnvelope(bytes.Replace(data, []byte(`"amount": 250000000`), []byte(`"amount": 350000000`), 1)); err != ErrEnvelopeChecksum {
+		t.Error("Modified envelope parsed", err)
+	}
+	defer useNethash("2a44f340d76ffc3df204c5f38cd355b7496c9065a1ade2ef92071436bd72e867")()
+	if err = offline.Verify(); err != ErrEnvelopeNetwork {
+		t.Error("Envelope of other network verified", err)
+	}
+}
+
+func TestEnvelopeNoNetwork(t *testing.T) {
+	signer := NewPassphraseSigner("this is a top secret passphrase")
+	templates := []Transaction{{Type: SENDARK, RecipientID: testRecipient(), Amount: 1, Fee: 10000000}}
+
+	restore := useNethash("")
+	if _, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), templates); err != ErrEnvelopeNoNetwork {
+		t.Error("Envelope created without network", err)
+	}
+	restore()
+
+	defer useNethash("6e84d08bd299ed97c212c886c98a57e36545c8f5d645ca7eeae63a8bd62d8988")()
+	envelope, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), templates)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = envelope.Sign(signer, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	defer useNethash("")()
+	if err = envelope.Verify(); err != ErrEnvelopeNoNetwork {
+		t.Error("Envelope verified without network", err)
+	}
+
+	envelope.Nethash = ""
+	envelope.seal()
+	if err = envelope.Sign(signer, nil); err != ErrEnvelopeNetwork {
+		t.Error("Envelope without nethash signed offline", err)
+	}
+}
+
+func TestEnvelopeSummary(t *testing.T) {
+	defer useNethash("6e84d08bd299ed97c212c886c98a57e36545c8f5d645ca7eeae63a8bd62d8988")()
+
+	signer := NewPassphraseSigner("this is a top secret passphrase")
+	envelope, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), []Transaction{
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 9007199254740993, Fee: 10000000},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	//amounts are exact, not rounded by float64
+	if !strings.HasPrefix(envelope.Summary[0], "1: transfer 90071992.54740993 to ") || !strings.Contains(envelope.Summary[0], "fee 0.1") {
+		t.Error("Wrong summary", envelope.Summary)
+	}
+
+	//checksum of forged summary is recomputed, the summary must still match the transactions
+	envelope.Summary[0] = "1: transfer 1 to " + testRecipient() + ", fee 0.1"
+	envelope.Checksum, _ = envelope.checksum()
+	data, _ := envelope.ToJSON()
+	if _, err = ParseEnvelope(data); err != ErrEnvelopeSummary {
+		t.Error("Envelope with forged summary parsed", err)
+	}
+}
+
+func TestEnvelopeV2(t *testing.T) {
+	defer useNethash("6e84d08bd299ed97c212c886c98a57e36545c8f5d645ca7eeae63a8bd62d8988")()
+	defer useV2Network(t, "7")()
+
+	signer := NewPassphraseSigner("this is a top secret passphrase")
+	_, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), []Transaction{
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 1},
+		{Type: SENDARK, RecipientID: testRecipient()},
+	})
+	if err == nil || err.Error() != "transaction 1: "+ErrTransactionAmount.Error() {
+		t.Error("Invalid template accepted", err)
+	}
+
+	envelope, err := NewEnvelope(hex.EncodeToString(signer.PublicKey()), []Transaction{
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 1},
+		{Type: SENDARK, RecipientID: testRecipient(), Amount: 2},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if envelope.Transactions[0].Nonce != 8 || envelope.Transactions[1].Nonce != 9 {
+		t.Error("Wrong nonces", envelope.Summary)
+	}
+	if err = envelope.Sign(signer, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = envelope.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//HTLC lock expiration types
+const (
+	HTLCExpirationTimestamp = 1 //Ark epoch timestamp, see GetTimestamp
+	HTLCExpirationHeight    = 2 //block height
+)
+
+//HTLCSecretLength is the length of HTLC unlock secret, its sha256 hash locks the funds
+const HTLCSecretLength = 32
+
+var (
+	//ErrHTLCVersion is returned when HTLC transaction is created on network without AIP-11 transactions
+	ErrHTLCVersion = errors.New("HTLC transactions need AIP-11 (v2) network")
+	//ErrHTLCSecret is returned for secrets and secret hashes not 32 bytes long, or secret not matching the lock
+	ErrHTLCSecret = errors.New("HTLC secret does not match secret hash")
+	//ErrHTLCExpiration is returned for unknown expiration type or expiration in the past
+	ErrHTLCExpiration = errors.New("HTLC expiration is invalid")
+	//ErrHTLCLockID is returned when claim or refund does not reference a lock transaction id
+	ErrHTLCLockID = errors.New("HTLC lock transaction id is invalid")
+	//ErrHTLCLock is returned when claim or refund does not belong to the lock or its sender is not allowed
+	ErrHTLCLock = errors.New("transaction does not release this HTLC lock")
+	//ErrHTLCExpired is returned for claims of expired locks
+	ErrHTLCExpired = errors.New("HTLC lock is expired")
+	//ErrHTLCNotExpired is returned for refunds of locks not yet expired
+	ErrHTLCNotExpired = errors.New("HTLC lock is not expired yet")
+)
+
+//HTLCExpiration is expiration of HTLC lock, after it the lock can not be claimed, only refunded
+type HTLCExpiration struct {
+	Type  byte
+	Value uint32
+}
+
+//HTLCExpireAtHeight returns lock expiration at block height
+func HTLCExpireAtHeight(height uint32) HTLCExpiration {
+	return HTLCExpiration{Type: HTLCExpirationHeight, Value: height}
+}
+
+//HTLCExpireAtTime returns lock expiration at time t
+func HTLCExpireAtTime(t time.Time) HTLCExpiration {
+	return HTLCExpiration{Type: HTLCExpirationTimestamp, Value: uint32(GetTimestamp(t))}
+}
+
+//expired reports whether expiration is reached at block height and Ark epoch timestamp
+func (e HTLCExpiration) expired(height uint32, timestamp int32) bool {
+	if e.Type == HTLCExpirationHeight {
+		return height >= e.Value
+	}
+	return timestamp >= 0 && uint32(timestamp) >= e.Value
+}
+
+//GenerateHTLCSecret returns random unlock secret and its sha256 hash used in the lock
+func GenerateHTLCSecret() ([]byte, []byte, error) {
+	secret := make([]byte, HTLCSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, nil, err
+	}
+	return secret, HTLCSecretHash(secret), nil
+}
+
+//HTLCSecretHash returns sha256 hash of unlock secret
+func HTLCSecretHash(secret []byte) []byte {
+	hash := sha256.Sum256(secret)
+	return hash[:]
+}
+
+//CreateHTLCLock creates transaction locking amount for recipient, who can claim it with the secret of secretHash
+//until expiration. After expiration the sender can refund it.
+func CreateHTLCLock(recipientID string, amount int64, secretHash []byte, expiration HTLCExpiration, vendorField, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateHTLCLockWithSigner(recipientID, amount, secretHash, expiration, vendorField, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateHTLCLockWithSigner creates HTLC lock signed by signer, see CreateHTLCLock
+func CreateHTLCLockWithSigner(recipientID string, amount int64, secretHash []byte, expiration HTLCExpiration, vendorField string, signer, secondSigner Signer) (*Transaction, error) {
+	if EnvironmentParams.Network.TransactionVersion < TransactionV2 {
+		return nil, ErrHTLCVersion
+	}
+	if err := arkcoin.ValidateAddress(recipientID, arkcoin.ActiveCoinConfig); err != nil {
+		return nil, err
+	}
+	if len(secretHash) != sha256.Size {
+		return nil, ErrHTLCSecret
+	}
+	if expiration.Type != HTLCExpirationHeight && (expiration.Type != HTLCExpirationTimestamp || expiration.expired(0, GetTime())) {
+		return nil, ErrHTLCExpiration
+	}
+
+	tx := Transaction{
+		Type:        HTLCLOCK,
+		RecipientID: recipientID,
+		Amount:      amount,
+		Fee:         EnvironmentParams.Fees.HTLCLock,
+		VendorField: vendorField,
+		Asset: map[string]string{
+			"secretHash":      hex.EncodeToString(secretHash),
+			"expirationType":  strconv.Itoa(int(expiration.Type)),
+			"expirationValue": strconv.FormatUint(uint64(expiration.Value), 10),
+		},
+	}
+	if tx.Fee == 0 {
+		tx.Fee = EnvironmentParams.Fees.Send
+	}
+	return tx.signAll(signer, secondSigner)
+}
+
+//CreateHTLCLockFromKey creates HTLC lock signed with private key, see CreateHTLCLock
+func CreateHTLCLockFromKey(recipientID string, amount int64, secretHash []byte, expiration HTLCExpiration, vendorField string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateHTLCLockWithSigner(recipientID, amount, secretHash, expiration, vendorField, NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//CreateHTLCClaim creates transaction claiming lock funds by revealing the unlock secret. Sender must be the lock recipient.
+func CreateHTLCClaim(lockTransactionID string, secret []byte, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateHTLCClaimWithSigner(lockTransactionID, secret, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateHTLCClaimWithSigner creates HTLC claim signed by signer, see CreateHTLCClaim
+func CreateHTLCClaimWithSigner(lockTransactionID string, secret []byte, signer, secondSigner Signer) (*Transaction, error) {
+	if len(secret) != HTLCSecretLength {
+		return nil, ErrHTLCSecret
+	}
+	return createHTLCRelease(HTLCCLAIM, lockTransactionID, map[string]string{"unlockSecret": hex.EncodeToString(secret)}, signer, secondSigner)
+}
+
+//CreateHTLCClaimFromKey creates HTLC claim signed with private key, see CreateHTLCClaim
+func CreateHTLCClaimFromKey(lockTransactionID string, secret []byte, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateHTLCClaimWithSigner(lockTransactionID, secret, NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//CreateHTLCRefund creates transaction returning expired lock funds. Sender must be the lock sender.
+func CreateHTLCRefund(lockTransactionID, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateHTLCRefundWithSigner(lockTransactionID, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateHTLCRefundWithSigner creates HTLC refund signed by signer, see CreateHTLCRefund
+func CreateHTLCRefundWithSigner(lockTransactionID string, signer, secondSigner Signer) (*Transaction, error) {
+	return createHTLCRelease(HTLCREFUND, lockTransactionID, map[string]string{}, signer, secondSigner)
+}
+
+//CreateHTLCRefundFromKey creates HTLC refund signed with private key, see CreateHTLCRefund
+func CreateHTLCRefundFromKey(lockTransactionID string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateHTLCRefundWithSigner(lockTransactionID, NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//createHTLCRelease creates claim or refund of lockTransactionID, claims and refunds have no fee
+func createHTLCRelease(txType byte, lockTransactionID string, asset map[string]string, signer, secondSigner Signer) (*Transaction, error) {
+	if EnvironmentParams.Network.TransactionVersion < TransactionV2 {
+		return nil, ErrHTLCVersion
+	}
+	if lockID, err := hex.DecodeString(lockTransactionID); err != nil || len(lockID) != 32 {
+		return nil, ErrHTLCLockID
+	}
+	asset["lockTransactionId"] = lockTransactionID
+	tx := Transaction{
+		Type:  txType,
+		Asset: asset,
+	}
+	return tx.signAll(signer, secondSigner)
+}
+
+//HTLCLockAsset returns secret hash and expiration of HTLC lock
+func (tx *Transaction) HTLCLockAsset() ([]byte, HTLCExpiration, error) {
+	if tx.Type != HTLCLOCK {
+		return nil, HTLCExpiration{}, ErrTransactionType
+	}
+	secretHash, err := hex.DecodeString(tx.Asset["secretHash"])
+	if err != nil || len(secretHash) != sha256.Size {
+		return nil, HTLCExpiration{}, ErrHTLCSecret
+	}
+	expirationType, err := strconv.Atoi(tx.Asset["expirationType"])
+	if err != nil || expirationType != HTLCExpirationTimestamp && expirationType != HTLCExpirationHeight {
+		return nil, HTLCExpiration{}, ErrHTLCExpiration
+	}
+	value, err := strconv.ParseUint(tx.Asset["expirationValue"], 10, 32)
+	if err != nil {
+		return nil, HTLCExpiration{}, ErrHTLCExpiration
+	}
+	return secretHash, HTLCExpiration{Type: byte(expirationType), Value: uint32(value)}, nil
+}
+
+//releases checks that claim or refund references lock and is sent by allowed sender
+func (tx *Transaction) releases(lock *Transaction) error {
+	if tx.Asset["lockTransactionId"] != lock.ID {
+		return ErrHTLCLock
+	}
+	if tx.Type == HTLCREFUND {
+		if tx.SenderPublicKey != lock.SenderPublicKey {
+			return ErrHTLCLock
+		}
+		return nil
+	}
+
+	senderKey, err := hex.DecodeString(tx.SenderPublicKey)
+	if err != nil {
+		return ErrHTLCLock
+	}
+	sender, err := arkcoin.NewPublicKey(senderKey, arkcoin.ActiveCoinConfig)
+	if err != nil || sender.Address() != lock.RecipientID {
+		return ErrHTLCLock
+	}
+	return nil
+}
+
+//VerifyHTLCClaim verifies that claim reveals the lock secret, is sent by the lock recipient and the lock
+//is not expired at block height and Ark epoch timestamp. Signatures are checked with Verify.
+func (tx *Transaction) VerifyHTLCClaim(lock *Transaction, height uint32, timestamp int32) error {
+	if tx.Type != HTLCCLAIM {
+		return ErrTransactionType
+	}
+	secretHash, expiration, err := lock.HTLCLockAsset()
+	if err != nil {
+		return err
+	}
+	if err = tx.releases(lock); err != nil {
+		return err
+	}
+	secret, err := hex.DecodeString(tx.Asset["unlockSecret"])
+	if err != nil || !bytes.Equal(HTLCSecretHash(secret), secretHash) {
+		return ErrHTLCSecret
+	}
+	if expiration.expired(height, timestamp) {
+		return ErrHTLCExpired
+	}
+	return nil
+}
+
+//VerifyHTLCRefund verifies that refund is sent by the lock sender and the lock is expired
+//at block height and Ark epoch timestamp. Signatures are checked with Verify.
+func (tx *Transaction) VerifyHTLCRefund(lock *Transaction, height uint32, timestamp int32) error {
+	if tx.Type != HTLCREFUND {
+		return ErrTransactionType
+	}
+	_, expiration, err := lock.HTLCLockAsset()
+	if err != nil {
+		return err
+	}
+	if err = tx.releases(lock); err != nil {
+		return err
+	}
+	if !expiration.expired(height, timestamp) {
+		return ErrHTLCNotExpired
+	}
+	return nil
+}
+
+//HTLCLock structure of an open lock returned by AIP-11 (v2) peers
+type HTLCLock struct {
+	LockID          string      `json:"lockId"`
+	Amount          json.Number `json:"amount"`
+	SecretHash      string      `json:"secretHash"`
+	SenderPublicKey string      `json:"senderPublicKey"`
+	RecipientID     string      `json:"recipientId"`
+	ExpirationType  byte        `json:"expirationType"`
+	ExpirationValue uint32      `json:"expirationValue"`
+	IsExpired       bool        `json:"isExpired"`
+	VendorField     string      `json:"vendorField"`
+}
+
+//HTLCLocksResponse structure for call /api/wallets/{id}/locks
+type HTLCLocksResponse struct {
+	Meta struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"meta"`
+	Data []HTLCLock `json:"data"`
+}
+
+//HTLCLocksQueryParams for paging wallet locks, peers return up to 100 locks per page
+type HTLCLocksQueryParams struct {
+	Page  int `url:"page,omitempty"`
+	Limit int `url:"limit,omitempty"`
+}
+
+//GetWalletLocks returns open HTLC locks sent by address (or public key)
+func (s *ArkClient) GetWalletLocks(address string, params HTLCLocksQueryParams) (HTLCLocksResponse, *http.Response, error) {
+	locksResp := new(HTLCLocksResponse)
+	errResp := new(ArkApiResponseError)
+
+	resp, err := s.sling.New().Get("api/wallets/"+address+"/locks").QueryStruct(&params).Receive(locksResp, errResp)
+	if err == nil && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		err = fmt.Errorf("locks of %s: %s %s", address, resp.Status, errResp.Message)
+	}
+	return *locksResp, resp, err
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dghubble/sling"
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+func TestHTLCSwap(t *testing.T) {
+	defer useV2Network(t, "")()
+
+	alice, bob := NewPassphraseSigner("alice passphrase"), NewPassphraseSigner("bob passphrase")
+	bobKey, _ := arkcoin.NewPublicKey(bob.PublicKey(), arkcoin.ActiveCoinConfig)
+
+	secret, secretHash, err := GenerateHTLCSecret()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	lock, err := CreateHTLCLockWithSigner(bobKey.Address(), 100000000, secretHash, HTLCExpireAtHeight(1000), "OTC swap", alice, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), lock.ToJSON())
+	if err = lock.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	claim, err := CreateHTLCClaimWithSigner(lock.ID, secret, bob, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if claim.Fee != 0 {
+		t.Error("Claim has fee", claim.Fee)
+	}
+	if err = claim.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = claim.VerifyHTLCClaim(lock, 999, 0); err != nil {
+		t.Error(err.Error())
+	}
+	if err = claim.VerifyHTLCClaim(lock, 1000, 0); err != ErrHTLCExpired {
+		t.Error("Claim of expired lock verified", err)
+	}
+
+	//claim with wrong secret or by other wallet
+	wrongSecret, _, _ := GenerateHTLCSecret()
+	wrongClaim, err := CreateHTLCClaimWithSigner(lock.ID, wrongSecret, bob, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = wrongClaim.VerifyHTLCClaim(lock, 1, 0); err != ErrHTLCSecret {
+		t.Error("Wrong secret verified", err)
+	}
+	aliceClaim, err := CreateHTLCClaimWithSigner(lock.ID, secret, alice, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = aliceClaim.VerifyHTLCClaim(lock, 1, 0); err != ErrHTLCLock {
+		t.Error("Claim by sender verified", err)
+	}
+
+	refund, err := CreateHTLCRefundWithSigner(lock.ID, alice, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = refund.VerifyHTLCRefund(lock, 999, 0); err != ErrHTLCNotExpired {
+		t.Error("Refund before expiration verified", err)
+	}
+	if err = refund.VerifyHTLCRefund(lock, 1000, 0); err != nil {
+		t.Error(err.Error())
+	}
+	bobRefund, err := CreateHTLCRefundWithSigner(lock.ID, bob, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = bobRefund.VerifyHTLCRefund(lock, 1000, 0); err != ErrHTLCLock {
+		t.Error("Refund by recipient verified", err)
+	}
+
+	for _, tx := range []*Transaction{lock, claim, refund} {
+		txBytes, _ := tx.toBytes(false, false)
+		parsed, err := FromBytes(txBytes)
+		if err != nil {
+			t.Error(tx.Type, err.Error())
+			continue
+		}
+		if parsed.ID != tx.ID || parsed.Asset["secretHash"] != tx.Asset["secretHash"] || parsed.Asset["unlockSecret"] != tx.Asset["unlockSecret"] {
+			t.Error("Wrong deserialized transaction", tx.Type, parsed.ToJSON())
+		}
+	}
+}
+
+func TestCreateHTLCLockErrors(t *testing.T) {
+	if _, err := CreateHTLCLock(testRecipient(), 1, make([]byte, 32), HTLCExpireAtHeight(10), "", "alice passphrase", ""); err != ErrHTLCVersion {
+		t.Error("HTLC created on legacy network", err)
+	}
+
+	defer useV2Network(t, "")()
+	if _, err := CreateHTLCLock(testRecipient(), 1, make([]byte, 31), HTLCExpireAtHeight(10), "", "alice passphrase", ""); err != ErrHTLCSecret {
+		t.Error("Short secret hash accepted", err)
+	}
+	if _, err := CreateHTLCLock(testRecipient(), 1, make([]byte, 32), HTLCExpireAtTime(time.Now().Add(-time.Hour)), "", "alice passphrase", ""); err != ErrHTLCExpiration {
+		t.Error("Expiration in the past accepted", err)
+	}
+	if _, err := CreateHTLCLock(testRecipient(), 1, make([]byte, 32), HTLCExpiration{Type: 3, Value: 10}, "", "alice passphrase", ""); err != ErrHTLCExpiration {
+		t.Error("Unknown expiration type accepted", err)
+	}
+	if _, err := CreateHTLCClaim("abcd", make([]byte, 32), "bob passphrase", ""); err != ErrHTLCLockID {
+		t.Error("Wrong lock id accepted", err)
+	}
+	if _, err := CreateHTLCClaim(hex.EncodeToString(make([]byte, 32)), make([]byte, 16), "bob passphrase", ""); err != ErrHTLCSecret {
+		t.Error("Short secret accepted", err)
+	}
+}
+
+func TestGetWalletLocks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/wallets/"+testRecipient()+"/locks" || r.URL.Query().Get("limit") != "10" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode":404,"error":"Not Found","message":"Wallet not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"meta":{"totalCount":1},"data":[{"lockId":"` + hex.EncodeToString(make([]byte, 32)) +
+			`","amount":"100000000","secretHash":"` + hex.EncodeToString(HTLCSecretHash(make([]byte, 32))) +
+			`","recipientId":"` + testRecipient() + `","expirationType":2,"expirationValue":1000,"isExpired":false,"vendorField":"OTC swap"}]}`))
+	}))
+	defer server.Close()
+	client := &ArkClient{sling: sling.New().Base(server.URL + "/")}
+
+	locks, _, err := client.GetWalletLocks(testRecipient(), HTLCLocksQueryParams{Limit: 10})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if locks.Meta.TotalCount != 1 || len(locks.Data) != 1 || locks.Data[0].Amount.String() != "100000000" || locks.Data[0].ExpirationType != HTLCExpirationHeight {
+		t.Error("Wrong locks", locks)
+	}
+
+	if _, _, err = client.GetWalletLocks("unknown", HTLCLocksQueryParams{}); err == nil {
+		t.Error("Locks of unknown wallet returned")
+	}
+}
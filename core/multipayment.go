@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//Multipayment limits, one transaction pays from MultiPaymentMinPayments to MultiPaymentMaxPayments recipients
+const (
+	MultiPaymentMinPayments = 2
+	MultiPaymentMaxPayments = 64
+)
+
+var (
+	//ErrMultiPaymentCount is returned when number of payments is not between MultiPaymentMinPayments and MultiPaymentMaxPayments
+	ErrMultiPaymentCount = fmt.Errorf("multipayment must have %d to %d payments", MultiPaymentMinPayments, MultiPaymentMaxPayments)
+	//ErrMultiPaymentAmount is returned for zero or negative payment amounts, or when total amount overflows
+	ErrMultiPaymentAmount = errors.New("multipayment amounts must be positive")
+	//ErrMultiPaymentVersion is returned when multipayment is created on network without AIP-11 transactions
+	ErrMultiPaymentVersion = errors.New("multipayment transactions need AIP-11 (v2) network")
+)
+
+//Payment is one recipient and amount (in satoshi) of a multipayment transaction
+type Payment struct {
+	Amount      int64  `json:"amount"`
+	RecipientID string `json:"recipientId"`
+}
+
+//CreateMultiPayment creates transaction paying all payments at once. Fee is paid once per transaction,
+//vendor field is shared by all recipients. Multipayment is an AIP-11 (v2) transaction type.
+func CreateMultiPayment(payments []Payment, vendorField, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateMultiPaymentWithSigner(payments, vendorField, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateMultiPaymentWithSigner creates multipayment transaction signed by signer, see CreateMultiPayment
+func CreateMultiPaymentWithSigner(payments []Payment, vendorField string, signer, secondSigner Signer) (*Transaction, error) {
+	if EnvironmentParams.Network.TransactionVersion < TransactionV2 {
+		return nil, ErrMultiPaymentVersion
+	}
+	if len(payments) < MultiPaymentMinPayments || len(payments) > MultiPaymentMaxPayments {
+		return nil, ErrMultiPaymentCount
+	}
+	if len(vendorField) > arkcoin.MaxVendorFieldLength {
+		return nil, ErrVendorFieldTooLong
+	}
+	var total int64
+	for _, payment := range payments {
+		if payment.Amount <= 0 || total > 1<<63-1-payment.Amount {
+			return nil, ErrMultiPaymentAmount
+		}
+		total += payment.Amount
+		if err := arkcoin.ValidateAddress(payment.RecipientID, arkcoin.ActiveCoinConfig); err != nil {
+			return nil, fmt.Errorf("recipient %s: %s", payment.RecipientID, err.Error())
+		}
+	}
+
+	tx := Transaction{
+		Type:        MULTIPAYMENT,
+		Fee:         MultiPaymentFee(),
+		VendorField: vendorField,
+		Payments:    append([]Payment(nil), payments...),
+	}
+	return tx.signAll(signer, secondSigner)
+}
+
+//CreateMultiPaymentFromKey creates multipayment transaction signed with private key, see CreateMultiPayment
+func CreateMultiPaymentFromKey(payments []Payment, vendorField string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateMultiPaymentWithSigner(payments, vendorField, NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//MultiPaymentFee returns fee of one multipayment transaction, transfer fee if network does not define it
+func MultiPaymentFee() int64 {
+	if EnvironmentParams.Fees.MultiPayment > 0 {
+		return EnvironmentParams.Fees.MultiPayment
+	}
+	return EnvironmentParams.Fees.Send
+}
+
+//SplitPayments splits payments into as few groups of at most MultiPaymentMaxPayments as possible.
+//Groups are balanced, so no group is left with a single payment unless there is only one payment.
+func SplitPayments(payments []Payment) [][]Payment {
+	if len(payments) == 0 {
+		return nil
+	}
+	groups := (len(payments) + MultiPaymentMaxPayments - 1) / MultiPaymentMaxPayments
+	result := make([][]Payment, 0, groups)
+	for i := 0; i < groups; i++ {
+		size := len(payments) / (groups - i)
+		if len(payments)%(groups-i) != 0 {
+			size++
+		}
+		result = append(result, payments[:size])
+		payments = payments[size:]
+	}
+	return result
+}
+
+//TotalAmount returns amount sent by transaction, sum of payments for multipayment
+func (tx *Transaction) TotalAmount() int64 {
+	if tx.Type != MULTIPAYMENT {
+		return tx.Amount
+	}
+	var total int64
+	for _, payment := range tx.Payments {
+		total += payment.Amount
+	}
+	return total
+}
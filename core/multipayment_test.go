@@ -0,0 +1,107 @@
+package core
+
+import (
+	"log"
+	"testing"
+)
+
+func testPayments(n int) []Payment {
+	payments := make([]Payment, n)
+	for i := range payments {
+		payments[i] = Payment{Amount: int64(i+1) * 1000, RecipientID: testRecipient()}
+	}
+	return payments
+}
+
+func TestMultiPayment(t *testing.T) {
+	defer useV2Network(t, "5")()
+
+	payments := testPayments(3)
+	tx, err := CreateMultiPayment(payments, "voters payout", "this is a top secret passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), tx.ToJSON())
+	if tx.Type != MULTIPAYMENT || tx.Amount != 0 || tx.RecipientID != "" || tx.Fee != MultiPaymentFee() {
+		t.Error("Wrong multipayment transaction", tx.Type, tx.Amount, tx.RecipientID, tx.Fee)
+	}
+	if tx.TotalAmount() != 6000 {
+		t.Error("Wrong total amount", tx.TotalAmount())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = tx.SecondVerify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	//payments are signed
+	tx.Payments[1].Amount++
+	if err = tx.Verify(); err == nil {
+		t.Error("Changed payment amount verified")
+	}
+	tx.Payments[1].Amount--
+
+	txBytes, _ := tx.toBytes(false, false)
+	parsed, err := FromBytes(txBytes)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed.ID != tx.ID || len(parsed.Payments) != 3 || parsed.Payments[2] != payments[2] {
+		t.Error("Wrong deserialized multipayment", parsed.ToJSON())
+	}
+	unsignedBytes, _ := tx.toBytes(true, true)
+	if _, err = FromBytes(unsignedBytes[:len(unsignedBytes)-10]); err != ErrTransactionTruncated {
+		t.Error("Truncated multipayment accepted", err)
+	}
+}
+
+func TestCreateMultiPaymentErrors(t *testing.T) {
+	if _, err := CreateMultiPayment(testPayments(2), "", "this is a top secret passphrase", ""); err != ErrMultiPaymentVersion {
+		t.Error("Legacy multipayment created", err)
+	}
+	defer useV2Network(t, "5")()
+
+	for _, n := range []int{0, 1, MultiPaymentMaxPayments + 1} {
+		if _, err := CreateMultiPayment(testPayments(n), "", "this is a top secret passphrase", ""); err != ErrMultiPaymentCount {
+			t.Error("Wrong number of payments accepted", n, err)
+		}
+	}
+	payments := testPayments(2)
+	payments[0].Amount = 0
+	if _, err := CreateMultiPayment(payments, "", "this is a top secret passphrase", ""); err != ErrMultiPaymentAmount {
+		t.Error("Zero amount accepted", err)
+	}
+	payments = testPayments(2)
+	payments[1].Amount = 1<<63 - 1
+	if _, err := CreateMultiPayment(payments, "", "this is a top secret passphrase", ""); err != ErrMultiPaymentAmount {
+		t.Error("Overflowing total accepted", err)
+	}
+	payments = testPayments(2)
+	payments[1].RecipientID = "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM26"
+	if _, err := CreateMultiPayment(payments, "", "this is a top secret passphrase", ""); err == nil {
+		t.Error("Invalid recipient accepted")
+	}
+}
+
+func TestSplitPayments(t *testing.T) {
+	sizes := map[int][]int{
+		0:   nil,
+		1:   {1},
+		64:  {64},
+		65:  {33, 32},
+		130: {44, 43, 43},
+	}
+	for n, expected := range sizes {
+		groups := SplitPayments(testPayments(n))
+		if len(groups) != len(expected) {
+			t.Error("Wrong number of groups", n, len(groups))
+			continue
+		}
+		for i, group := range groups {
+			if len(group) != expected[i] {
+				t.Error("Wrong group size", n, i, len(group))
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//Multisignature limits of the Ark protocol
+const (
+	MultiSignatureMaxKeys     = 15
+	MultiSignatureMaxLifetime = 72 //hours
+)
+
+var (
+	//ErrMultiSignatureKeysgroup is returned for empty, too big or duplicated keysgroup, invalid keys or keysgroup containing sender
+	ErrMultiSignatureKeysgroup = errors.New("multisignature keysgroup must have 1 to 15 distinct public keys, without the sender")
+	//ErrMultiSignatureMin is returned when min is not between 1 and keysgroup size
+	ErrMultiSignatureMin = errors.New("multisignature min must be between 1 and keysgroup size")
+	//ErrMultiSignatureLifetime is returned when lifetime is not between 1 and 72 hours
+	ErrMultiSignatureLifetime = errors.New("multisignature lifetime must be between 1 and 72 hours")
+	//ErrMultiSignatureSigned is returned by MultiSign when signer already signed the transaction
+	ErrMultiSignatureSigned = errors.New("transaction is already signed by this key")
+	//ErrMultiSignatureUnknown is returned when signature is not valid for any unused keysgroup member
+	ErrMultiSignatureUnknown = errors.New("signature does not belong to keysgroup member")
+	//ErrMultiSignatureNotEnough is returned when there are less valid signatures than min
+	ErrMultiSignatureNotEnough = errors.New("not enough multisignature signatures")
+)
+
+//CreateMultiSignature creates transaction registering the sender account as multisignature account.
+//min of keysgroup (hex public keys, "+" prefix optional) must sign later transactions, which can wait
+//for signatures lifetime hours (ignored on AIP-11 networks). Every keysgroup member must sign the registration with MultiSign.
+func CreateMultiSignature(min, lifetime int, keysgroup []string, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateMultiSignatureWithSigner(min, lifetime, keysgroup, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateMultiSignatureWithSigner creates multisignature registration signed by signer, see CreateMultiSignature
+func CreateMultiSignatureWithSigner(min, lifetime int, keysgroup []string, signer, secondSigner Signer) (*Transaction, error) {
+	keys, err := normalizeKeysgroup(keysgroup, hex.EncodeToString(signer.PublicKey()))
+	if err != nil {
+		return nil, err
+	}
+	if min < 1 || min > len(keys) {
+		return nil, ErrMultiSignatureMin
+	}
+	//AIP-11 registrations have no lifetime
+	v2 := EnvironmentParams.Network.TransactionVersion >= TransactionV2
+	if !v2 && (lifetime < 1 || lifetime > MultiSignatureMaxLifetime) {
+		return nil, ErrMultiSignatureLifetime
+	}
+
+	members := make([]string, len(keys))
+	for i, key := range keys {
+		members[i] = "+" + key
+	}
+	tx := Transaction{
+		Type: MULTISIGNATURE,
+		Fee:  EnvironmentParams.Fees.MultiSignature * int64(len(keys)+1),
+		Asset: map[string]string{
+			"min":       strconv.Itoa(min),
+			"keysgroup": strings.Join(members, ","),
+		},
+	}
+	if !v2 {
+		tx.Asset["lifetime"] = strconv.Itoa(lifetime)
+	}
+	return tx.signAll(signer, secondSigner)
+}
+
+//CreateMultiSignatureFromKey creates multisignature registration signed with private key, see CreateMultiSignature
+func CreateMultiSignatureFromKey(min, lifetime int, keysgroup []string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateMultiSignatureWithSigner(min, lifetime, keysgroup, NewKeySigner(key), secondKeySigner(secondKey))
+}
+
+//normalizeKeysgroup strips "+" prefixes and validates keysgroup public keys
+func normalizeKeysgroup(keysgroup []string, senderPublicKey string) ([]string, error) {
+	if len(keysgroup) < 1 || len(keysgroup) > MultiSignatureMaxKeys {
+		return nil, ErrMultiSignatureKeysgroup
+	}
+	keys := make([]string, 0, len(keysgroup))
+	seen := make(map[string]bool)
+	for _, key := range keysgroup {
+		key = strings.ToLower(strings.TrimPrefix(key, "+"))
+		keyBytes, err := hex.DecodeString(key)
+		if err != nil || seen[key] || key == senderPublicKey {
+			return nil, ErrMultiSignatureKeysgroup
+		}
+		if _, err = arkcoin.NewPublicKey(keyBytes, arkcoin.ActiveCoinConfig); err != nil {
+			return nil, ErrMultiSignatureKeysgroup
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+//MultiSignatureAsset returns keysgroup (without "+" prefixes), min and lifetime of multisignature registration
+func (tx *Transaction) MultiSignatureAsset() ([]string, int, int, error) {
+	if tx.Type != MULTISIGNATURE {
+		return nil, 0, 0, ErrTransactionType
+	}
+	min, err := strconv.Atoi(tx.Asset["min"])
+	if err != nil {
+		return nil, 0, 0, ErrMultiSignatureMin
+	}
+	//AIP-11 registrations have no lifetime
+	lifetime := 0
+	if tx.Version < TransactionV2 || tx.Asset["lifetime"] != "" {
+		if lifetime, err = strconv.Atoi(tx.Asset["lifetime"]); err != nil {
+			return nil, 0, 0, ErrMultiSignatureLifetime
+		}
+	}
+	keys, err := normalizeKeysgroup(strings.Split(tx.Asset["keysgroup"], ","), tx.SenderPublicKey)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return keys, min, lifetime, nil
+}
+
+//multiSignatureHash returns hash signed by keysgroup members - the same as signed by the sender
+func (tx *Transaction) multiSignatureHash() ([]byte, error) {
+	txBytes, err := tx.toBytes(true, true)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(txBytes)
+	return hash[:], nil
+}
+
+//MultiSign adds keysgroup member signature to Signatures. Transaction must already be signed
+//by the sender, as signatures are not part of the signed bytes they can be collected in any order.
+func (tx *Transaction) MultiSign(signer Signer) error {
+	hash, err := tx.multiSignatureHash()
+	if err != nil {
+		return err
+	}
+	key, err := arkcoin.NewPublicKey(signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return err
+	}
+	for _, signature := range tx.Signatures {
+		if sig, err := hex.DecodeString(signature); err == nil && key.VerifyStrict(sig, hash) == nil {
+			return ErrMultiSignatureSigned
+		}
+	}
+
+	sig, err := signer.SignHash(hash)
+	if err != nil {
+		return err
+	}
+	tx.Signatures = append(tx.Signatures, hex.EncodeToString(sig))
+	return nil
+}
+
+//VerifyMultiSignatures verifies that Signatures hold at least min valid signatures of distinct keysgroup members.
+//Every signature must belong to a keysgroup member. For multisignature registration use
+//MultiSignatureAsset keysgroup and require all of them to sign.
+func (tx *Transaction) VerifyMultiSignatures(keysgroup []string, min int) error {
+	hash, err := tx.multiSignatureHash()
+	if err != nil {
+		return err
+	}
+	keys := make([]*arkcoin.PublicKey, 0, len(keysgroup))
+	for _, member := range keysgroup {
+		keyBytes, err := hex.DecodeString(strings.TrimPrefix(member, "+"))
+		if err != nil {
+			return ErrMultiSignatureKeysgroup
+		}
+		key, err := arkcoin.NewPublicKey(keyBytes, arkcoin.ActiveCoinConfig)
+		if err != nil {
+			return ErrMultiSignatureKeysgroup
+		}
+		keys = append(keys, key)
+	}
+
+	used := make([]bool, len(keys))
+	for _, signature := range tx.Signatures {
+		sig, err := hex.DecodeString(signature)
+		if err != nil {
+			return ErrMultiSignatureUnknown
+		}
+		found := false
+		for i, key := range keys {
+			if !used[i] && key.VerifyStrict(sig, hash) == nil {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return ErrMultiSignatureUnknown
+		}
+	}
+	if len(tx.Signatures) < min {
+		return ErrMultiSignatureNotEnough
+	}
+	return nil
+}
+
+//PostSignatureResponse structure for call /peer/signatures
+type PostSignatureResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+type multiSignaturePayload struct {
+	Signature struct {
+		Transaction string `json:"transaction"`
+		Signature   string `json:"signature"`
+	} `json:"signature"`
+}
+
+//PostMultiSignature sends keysgroup member signature of a pending multisignature transaction to the peer.
+//Transactions with all needed Signatures can be posted with PostTransaction.
+func (s *ArkClient) PostMultiSignature(transactionID, signature string) (PostSignatureResponse, *http.Response, error) {
+	respSig := new(PostSignatureResponse)
+	errSig := new(ArkApiResponseError)
+
+	var payload multiSignaturePayload
+	payload.Signature.Transaction = transactionID
+	payload.Signature.Signature = signature
+	resp, err := s.sling.New().Post("peer/signatures").BodyJSON(payload).Receive(respSig, errSig)
+	if err == nil {
+		err = errSig
+	}
+
+	return *respSig, resp, err
+}
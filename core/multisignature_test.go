@@ -0,0 +1,97 @@
+package core
+
+import (
+	"encoding/hex"
+	"log"
+	"testing"
+)
+
+func TestMultiSignature(t *testing.T) {
+	members := []Signer{NewPassphraseSigner("first member"), NewPassphraseSigner("second member"), NewPassphraseSigner("third member")}
+	var keysgroup []string
+	for _, m := range members {
+		keysgroup = append(keysgroup, hex.EncodeToString(m.PublicKey()))
+	}
+
+	registration, err := CreateMultiSignature(2, 24, keysgroup, "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if registration.Fee != EnvironmentParams.Fees.MultiSignature*4 {
+		t.Error("Wrong fee", registration.Fee)
+	}
+	keys, min, lifetime, err := registration.MultiSignatureAsset()
+	if err != nil || len(keys) != 3 || min != 2 || lifetime != 24 {
+		t.Error("Wrong multisignature asset", keys, min, lifetime, err)
+	}
+
+	//registration must be signed by every member
+	for _, m := range members {
+		if err = registration.MultiSign(m); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if err = registration.MultiSign(members[0]); err != ErrMultiSignatureSigned {
+		t.Error("Second signature of the same member accepted", err)
+	}
+	if err = registration.VerifyMultiSignatures(keys, len(keys)); err != nil {
+		t.Error(err.Error())
+	}
+	if err = registration.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	//registration round trips through FromBytes, signatures are not serialized
+	txBytes, _ := registration.toBytes(false, false)
+	parsed, err := FromBytes(txBytes)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed.ID != registration.ID || parsed.Asset["keysgroup"] != registration.Asset["keysgroup"] {
+		t.Error("Wrong deserialized registration", parsed.ToJSON())
+	}
+
+	//2 of 3 members sign transfer from the multisignature account
+	tx, err := CreateTransaction(testRecipient(), 100000000, "team costs", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx.MultiSign(members[2])
+	if err = tx.VerifyMultiSignatures(keysgroup, 2); err != ErrMultiSignatureNotEnough {
+		t.Error("One signature accepted for 2 of 3", err)
+	}
+	tx.MultiSign(members[0])
+	if err = tx.VerifyMultiSignatures(keysgroup, 2); err != nil {
+		t.Error(err.Error())
+	}
+
+	tx.MultiSign(NewPassphraseSigner("not a member"))
+	if err = tx.VerifyMultiSignatures(keysgroup, 2); err != ErrMultiSignatureUnknown {
+		t.Error("Signature of non member accepted", err)
+	}
+	log.Println(t.Name(), "Success")
+}
+
+func TestCreateMultiSignatureErrors(t *testing.T) {
+	sender := NewPassphraseSigner("this is a top secret passphrase")
+	member := hex.EncodeToString(NewPassphraseSigner("first member").PublicKey())
+
+	cases := []struct {
+		min, lifetime int
+		keysgroup     []string
+		err           error
+	}{
+		{1, 24, nil, ErrMultiSignatureKeysgroup},
+		{1, 24, []string{member, "+" + member}, ErrMultiSignatureKeysgroup},
+		{1, 24, []string{member, hex.EncodeToString(sender.PublicKey())}, ErrMultiSignatureKeysgroup},
+		{1, 24, []string{"not a key"}, ErrMultiSignatureKeysgroup},
+		{2, 24, []string{member}, ErrMultiSignatureMin},
+		{0, 24, []string{member}, ErrMultiSignatureMin},
+		{1, 73, []string{member}, ErrMultiSignatureLifetime},
+	}
+	for _, c := range cases {
+		if _, err := CreateMultiSignatureWithSigner(c.min, c.lifetime, c.keysgroup, sender, nil); err != c.err {
+			t.Error("Expected", c.err, "got", err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"net/http"
 	"strconv"
@@ -33,7 +34,9 @@ type ArkClient struct {
 }
 
 func init() {
-	switchNetwork(MAINNET)
+	if err := switchNetwork(MAINNET); err != nil {
+		log.Println(err.Error())
+	}
 }
 
 //NewArkClient creations with supported network
@@ -81,7 +84,9 @@ func (s *ArkClient) SwitchPeer() *ArkClient {
 
 	//IF internal PeerList is empty - we do a full switch network - init from start
 	if len(EnvironmentParams.Network.PeerList) == 0 {
-		switchNetwork(EnvironmentParams.Network.Type)
+		if err := switchNetwork(EnvironmentParams.Network.Type); err != nil {
+			log.Println(err.Error())
+		}
 		return NewArkClient(nil)
 	}
 
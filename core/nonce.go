@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//ErrWalletNonce is returned when peer does not return the wallet nonce
+var ErrWalletNonce = errors.New("wallet nonce not returned by peer")
+
+//walletResponse structure for AIP-11 (v2) call /api/wallets/{id}
+type walletResponse struct {
+	Data struct {
+		Address string `json:"address"`
+		Nonce   string `json:"nonce"`
+	} `json:"data"`
+}
+
+//GetWalletNonce returns nonce of the last transaction sent from address (or public key), 0 for unknown wallets.
+//Only AIP-11 (v2) peers have nonces, the next transaction must use nonce+1.
+func (s *ArkClient) GetWalletNonce(address string) (uint64, *http.Response, error) {
+	walletResp := new(walletResponse)
+	errResp := new(ArkApiResponseError)
+
+	resp, err := s.sling.New().Get("api/wallets/"+address).Receive(walletResp, errResp)
+	if err != nil {
+		return 0, resp, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, resp, nil
+	}
+	if walletResp.Data.Nonce == "" {
+		return 0, resp, ErrWalletNonce
+	}
+	nonce, err := strconv.ParseUint(walletResp.Data.Nonce, 10, 64)
+	if err != nil {
+		return 0, resp, ErrWalletNonce
+	}
+	return nonce, resp, nil
+}
+
+//Nonces hands out nonces of AIP-11 transactions created by this package
+var Nonces = NewNonceTracker(nil)
+
+//NonceTracker hands out consecutive sender nonces. The first nonce of a sender is fetched from the
+//network, the following ones are counted locally, so many transactions can be created before sending.
+type NonceTracker struct {
+	mu     sync.Mutex
+	client *ArkClient
+	last   map[string]uint64
+}
+
+//NewNonceTracker returns tracker fetching nonces with client, nil uses a new client of the active peer
+func NewNonceTracker(client *ArkClient) *NonceTracker {
+	return &NonceTracker{client: client, last: make(map[string]uint64)}
+}
+
+//Next returns the next nonce of sender with hex senderPublicKey
+func (n *NonceTracker) Next(senderPublicKey string) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	last, ok := n.last[senderPublicKey]
+	if !ok {
+		keyBytes, err := hex.DecodeString(senderPublicKey)
+		if err != nil {
+			return 0, err
+		}
+		key, err := arkcoin.NewPublicKey(keyBytes, arkcoin.ActiveCoinConfig)
+		if err != nil {
+			return 0, err
+		}
+		client := n.client
+		if client == nil {
+			client = NewArkClient(nil)
+		}
+		if last, _, err = client.GetWalletNonce(key.Address()); err != nil {
+			return 0, err
+		}
+	}
+	n.last[senderPublicKey] = last + 1
+	return last + 1, nil
+}
+
+//Reset forgets counted nonces of sender, so the next one is fetched from the network again.
+//Call it when created transactions were not sent or were rejected.
+func (n *NonceTracker) Reset(senderPublicKey string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.last, senderPublicKey)
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+//Rebroadcaster defaults
+const (
+	DefaultRebroadcastAttempts = 3
+	DefaultRebroadcastMaxAge   = time.Hour
+	DefaultRebroadcastPeers    = 3
+)
+
+//ErrRebroadcastID is returned by Watch when transaction can not be restored from its serialized bytes
+var ErrRebroadcastID = errors.New("transaction id does not match its serialized bytes")
+
+//Rebroadcaster watches sent transactions with a ConfirmationTracker and posts the dropped ones again to
+//fresh peers of the active network, until they are confirmed or their attempts or age run out.
+//Transactions are posted exactly as they were signed, they are restored from bytes serialized by Watch
+//(multisignature keysgroup signatures are not serialized, they are copied).
+type Rebroadcaster struct {
+	Tracker     *ConfirmationTracker
+	MaxAttempts int           //rebroadcasts per transaction
+	MaxAge      time.Duration //transactions older than MaxAge (by timestamp) are not rebroadcasted
+	Peers       int           //number of peers posted to per rebroadcast
+	posted      []map[string]bool
+	signed      []*Transaction
+	created     []time.Time
+}
+
+//NewRebroadcaster returns rebroadcaster of transactions with tracker of depth confirmations asking peers, see NewConfirmationTracker
+func NewRebroadcaster(depth int, peers ...Peer) *Rebroadcaster {
+	return &Rebroadcaster{
+		Tracker:     NewConfirmationTracker(depth, peers...),
+		MaxAttempts: DefaultRebroadcastAttempts,
+		MaxAge:      DefaultRebroadcastMaxAge,
+		Peers:       DefaultRebroadcastPeers,
+	}
+}
+
+//Watch tracks transactions like ConfirmationTracker.Wait and rebroadcasts dropped ones. Rebroadcasted
+//transactions are pending again, the report holds the number of rebroadcasts of each transaction.
+func (r *Rebroadcaster) Watch(ctx context.Context, transactions []*Transaction) (ConfirmationReport, error) {
+	ids := make([]string, len(transactions))
+	r.signed = make([]*Transaction, len(transactions))
+	r.posted = make([]map[string]bool, len(transactions))
+	r.created = make([]time.Time, len(transactions))
+	for ix, tx := range transactions {
+		txBytes, err := tx.toBytes(false, false)
+		if err != nil {
+			return ConfirmationReport{}, err
+		}
+		restored, err := FromBytes(txBytes)
+		if err != nil || restored.ID != tx.ID {
+			return ConfirmationReport{}, ErrRebroadcastID
+		}
+		restored.Signatures = append([]string(nil), tx.Signatures...)
+		//AIP-11 bytes carry no timestamp, the age is taken from the original transaction
+		r.created[ix] = GetTransactionTime(tx.Timestamp)
+		ids[ix], r.signed[ix], r.posted[ix] = tx.ID, restored, make(map[string]bool)
+	}
+
+	tracker := *r.Tracker
+	tracker.dropped = r.rebroadcast
+	return tracker.Wait(ctx, ids)
+}
+
+//rebroadcast posts dropped transaction ix to fresh peers, it returns false when the transaction is
+//out of attempts or too old, or no peer accepted it
+func (r *Rebroadcaster) rebroadcast(ctx context.Context, ix int, status *TransactionStatus) bool {
+	tx := r.signed[ix]
+	if status.Rebroadcasts >= r.MaxAttempts {
+		log.Println("Transaction", tx.ID, "dropped, giving up after", status.Rebroadcasts, "rebroadcasts")
+		return false
+	}
+	if age := time.Since(r.created[ix]); age > r.MaxAge {
+		log.Println("Transaction", tx.ID, "dropped, not rebroadcasting transaction older than", r.MaxAge)
+		return false
+	}
+
+	status.Rebroadcasts++
+	accepted := false
+	for _, peer := range r.freshPeers(ix) {
+		res, resp, _ := postTransaction(ctx, newPeerClient(peer), tx)
+		switch {
+		case resp == nil:
+			log.Println("Transaction", tx.ID, "rebroadcast", status.Rebroadcasts, "to", peerURL(peer), "failed, peer not responding")
+		case res.Success:
+			log.Println("Transaction", tx.ID, "rebroadcast", status.Rebroadcasts, "to", peerURL(peer), "accepted")
+			accepted = true
+		default:
+			log.Println("Transaction", tx.ID, "rebroadcast", status.Rebroadcasts, "to", peerURL(peer), "rejected:", res.Message, res.Error)
+		}
+	}
+	return accepted
+}
+
+//freshPeers returns random peers of the active network not posted to yet, already used ones when
+//there are not enough fresh peers
+func (r *Rebroadcaster) freshPeers(ix int) []Peer {
+	var fresh, used []Peer
+	for _, i := range rand.Perm(len(EnvironmentParams.Network.PeerList)) {
+		peer := EnvironmentParams.Network.PeerList[i]
+		if r.posted[ix][peerURL(peer)] {
+			used = append(used, peer)
+		} else {
+			fresh = append(fresh, peer)
+		}
+	}
+	if len(fresh) == 0 && len(used) == 0 {
+		fresh = []Peer{EnvironmentParams.Network.ActivePeer}
+	}
+
+	peers := append(fresh, used...)
+	if r.Peers > 0 && len(peers) > r.Peers {
+		peers = peers[:r.Peers]
+	}
+	for _, peer := range peers {
+		r.posted[ix][peerURL(peer)] = true
+	}
+	return peers
+}
+
+//postTransaction posts tx to client peer, the request is cancelled with ctx
+func postTransaction(ctx context.Context, client *ArkClient, tx *Transaction) (PostTransactionResponse, *http.Response, error) {
+	respTr := new(PostTransactionResponse)
+	req, err := client.sling.New().Post("peer/transactions").BodyJSON(TransactionPayload{Transactions: []*Transaction{tx}}).Request()
+	if err != nil {
+		return *respTr, nil, err
+	}
+	resp, err := client.sling.Do(req.WithContext(ctx), respTr, new(ArkApiResponseError))
+	return *respTr, resp, err
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+//rebroadcastPeer returns peer of test server keeping posted transactions in the pool for two requests,
+//sent is in the pool already. Posted transactions are recorded.
+func rebroadcastPeer(sent string) (Peer, func() []Transaction, func()) {
+	var mu sync.Mutex
+	var posted []Transaction
+	inPool := map[string]int{sent: 2}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := r.URL.Query().Get("id")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/peer/transactions":
+			var payload TransactionPayload
+			json.NewDecoder(r.Body).Decode(&payload)
+			for _, tx := range payload.Transactions {
+				posted = append(posted, *tx)
+				inPool[tx.ID] = 2
+			}
+			w.Write([]byte(`{"success":true,"transactionIds":[]}`))
+		case r.URL.Path == "/api/transactions/unconfirmed/get" && inPool[id] > 0:
+			inPool[id]--
+			w.Write([]byte(`{"success":true,"transaction":{"id":"` + id + `","type":0}}`))
+		default:
+			w.Write([]byte(`{"success":false,"error":"Transaction not found"}`))
+		}
+	}))
+	address, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(address.Port())
+	return Peer{IP: address.Hostname(), Port: port}, func() []Transaction {
+		mu.Lock()
+		defer mu.Unlock()
+		return posted
+	}, server.Close
+}
+
+func TestRebroadcaster(t *testing.T) {
+	tx, err := CreateTransaction(testRecipient(), 100000000, "rebroadcast", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	testRebroadcaster(t, tx)
+}
+
+func TestRebroadcasterV2(t *testing.T) {
+	defer useV2Network(t, "5")()
+
+	tx, err := CreateTransaction(testRecipient(), 100000000, "rebroadcast", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	testRebroadcaster(t, tx)
+}
+
+//testRebroadcaster watches signed tx dropped by the test peer, it must be rebroadcasted until its attempts
+//run out, and not at all when it is too old
+func testRebroadcaster(t *testing.T, tx *Transaction) {
+	peer, posted, closePeer := rebroadcastPeer(tx.ID)
+	defer closePeer()
+	peerList := EnvironmentParams.Network.PeerList
+	EnvironmentParams.Network.PeerList = []Peer{peer}
+	defer func() {
+		EnvironmentParams.Network.PeerList = peerList
+	}()
+
+	rebroadcaster := NewRebroadcaster(1, peer)
+	rebroadcaster.MaxAttempts = 2
+	rebroadcaster.Tracker.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := rebroadcaster.Watch(ctx, []*Transaction{tx})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), report)
+
+	if status := report.Transactions[0]; status.Status != StatusDropped || status.Rebroadcasts != 2 {
+		t.Error("Wrong status", status)
+	}
+	if len(posted()) != 2 {
+		t.Fatal("Wrong number of rebroadcasts", len(posted()))
+	}
+	for _, sent := range posted() {
+		if sent.ID != tx.ID || sent.Signature != tx.Signature || sent.Verify() != nil {
+			t.Error("Rebroadcasted transaction differs", sent.ToJSON())
+		}
+	}
+
+	peer, _, closeOther := rebroadcastPeer(tx.ID)
+	defer closeOther()
+	EnvironmentParams.Network.PeerList = []Peer{peer}
+	rebroadcaster = NewRebroadcaster(1, peer)
+	rebroadcaster.MaxAge = time.Nanosecond
+	rebroadcaster.Tracker.Interval = 10 * time.Millisecond
+	report, _ = rebroadcaster.Watch(ctx, []*Transaction{tx})
+	if status := report.Transactions[0]; status.Status != StatusDropped || status.Rebroadcasts != 0 {
+		t.Error("Old transaction rebroadcasted", status)
+	}
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dghubble/sling"
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//Signer signs transaction hashes. Implementations can hold the key in memory (PassphraseSigner, KeySigner)
+//or ask another process or host for signatures (RemoteSigner), so the secret never reaches the payout host.
+type Signer interface {
+	//PublicKey returns compressed serialized public key of the signer
+	PublicKey() []byte
+	//SignHash returns DER encoded signature of hash
+	SignHash(hash []byte) ([]byte, error)
+}
+
+//KeySigner signs with PrivateKey held in memory
+type KeySigner struct {
+	key *arkcoin.PrivateKey
+}
+
+//NewKeySigner returns Signer for private key
+func NewKeySigner(key *arkcoin.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+//NewPassphraseSigner returns Signer for account passphrase, key is derived once
+func NewPassphraseSigner(passphrase string) *KeySigner {
+	return NewKeySigner(arkcoin.NewPrivateKeyFromPassword(passphrase, arkcoin.ActiveCoinConfig))
+}
+
+//ErrWIFUncompressed is returned for private keys in uncompressed WIF format, Ark uses compressed public keys
+var ErrWIFUncompressed = errors.New("uncompressed WIF is not supported")
+
+//NewWIFSigner returns Signer for private key in compressed WIF format of the active network
+func NewWIFSigner(wif string) (*KeySigner, error) {
+	key, err := arkcoin.FromWIF(wif, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(key.PublicKey.Serialize()) != 33 {
+		return nil, ErrWIFUncompressed
+	}
+	return NewKeySigner(key), nil
+}
+
+//PublicKey returns compressed serialized public key, also for keys marked uncompressed
+func (s *KeySigner) PublicKey() []byte {
+	return s.key.PublicKey.SerializeCompressed()
+}
+
+//SignHash signs hash with the private key
+func (s *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	return s.key.Sign(hash)
+}
+
+//ErrRemoteSignature is returned when remote signer returns signature not matching its public key
+var ErrRemoteSignature = errors.New("remote signer returned invalid signature")
+
+//RemoteSignerError is returned when remote signer refuses or fails to sign
+type RemoteSignerError struct {
+	Message string `json:"error"`
+}
+
+//Error is to implement Error interface.
+func (e RemoteSignerError) Error() string {
+	return "remote signer: " + e.Message
+}
+
+type remotePublicKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+type remoteSignRequest struct {
+	Hash string `json:"hash"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+//RemoteSignerTimeout limits every request to the signer service, a stalled service must not block payouts
+var RemoteSignerTimeout = 30 * time.Second
+
+//RemoteSigner asks signer service (see SignerHandler) for signatures over HTTP or Unix socket
+type RemoteSigner struct {
+	sling     *sling.Sling
+	publicKey []byte
+}
+
+//NewRemoteSigner connects to signer service at address and fetches its public key.
+//Address is http(s)://host:port/path or unix:///path/to/socket, token is the shared secret of the
+//service (see SignerHandler), empty when the service has none.
+func NewRemoteSigner(address, token string) (*RemoteSigner, error) {
+	client := &http.Client{Timeout: RemoteSignerTimeout}
+	base := strings.TrimSuffix(address, "/") + "/"
+	if strings.HasPrefix(address, "unix://") {
+		socket := strings.TrimPrefix(address, "unix://")
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		base = "http://unix/"
+	}
+
+	s := &RemoteSigner{sling: sling.New().Client(client).Base(base)}
+	if token != "" {
+		s.sling.Set("Authorization", "Bearer "+token)
+	}
+	pubResp := new(remotePublicKeyResponse)
+	signerErr := new(RemoteSignerError)
+	resp, err := s.sling.New().Get("publickey").Receive(pubResp, signerErr)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, *signerErr
+	}
+	if s.publicKey, err = hex.DecodeString(pubResp.PublicKey); err != nil {
+		return nil, err
+	}
+	if _, err = arkcoin.NewPublicKey(s.publicKey, arkcoin.ActiveCoinConfig); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//PublicKey returns public key received from the signer service
+func (s *RemoteSigner) PublicKey() []byte {
+	return s.publicKey
+}
+
+//SignHash sends hash to the signer service. Returned signature is verified against the signer public key.
+func (s *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	signResp := new(remoteSignResponse)
+	signerErr := new(RemoteSignerError)
+	resp, err := s.sling.New().Post("sign").BodyJSON(remoteSignRequest{Hash: hex.EncodeToString(hash)}).Receive(signResp, signerErr)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, *signerErr
+	}
+
+	sig, err := hex.DecodeString(signResp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	key, err := arkcoin.NewPublicKey(s.publicKey, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return nil, err
+	}
+	if key.VerifyStrict(sig, hash) != nil {
+		return nil, ErrRemoteSignature
+	}
+	return sig, nil
+}
+
+//SignerHandler serves signer over HTTP for RemoteSigner clients: GET publickey and POST sign.
+//Every hash sent is signed, so requests must carry token as bearer token when it is not empty.
+//Serve it without token only on a Unix socket not accessible by other users.
+func SignerHandler(signer Signer, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/publickey", func(w http.ResponseWriter, r *http.Request) {
+		if !signerAuthorized(r, token) {
+			writeSignerJSON(w, http.StatusUnauthorized, RemoteSignerError{Message: "unauthorized"})
+			return
+		}
+		writeSignerJSON(w, http.StatusOK, remotePublicKeyResponse{PublicKey: hex.EncodeToString(signer.PublicKey())})
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if !signerAuthorized(r, token) {
+			writeSignerJSON(w, http.StatusUnauthorized, RemoteSignerError{Message: "unauthorized"})
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeSignerJSON(w, http.StatusMethodNotAllowed, RemoteSignerError{Message: "POST required"})
+			return
+		}
+		var req remoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeSignerJSON(w, http.StatusBadRequest, RemoteSignerError{Message: err.Error()})
+			return
+		}
+		hash, err := hex.DecodeString(req.Hash)
+		if err != nil || len(hash) != 32 {
+			writeSignerJSON(w, http.StatusBadRequest, RemoteSignerError{Message: "hash must be 32 hex encoded bytes"})
+			return
+		}
+		sig, err := signer.SignHash(hash)
+		if err != nil {
+			writeSignerJSON(w, http.StatusInternalServerError, RemoteSignerError{Message: err.Error()})
+			return
+		}
+		writeSignerJSON(w, http.StatusOK, remoteSignResponse{Signature: hex.EncodeToString(sig)})
+	})
+	return mux
+}
+
+//signerAuthorized reports whether request carries bearer token, every request is authorized without token
+func signerAuthorized(r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+func writeSignerJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
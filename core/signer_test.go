@@ -0,0 +1,213 @@
+package core
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kristjank/ark-go/arkcoin"
+	"github.com/kristjank/ark-go/arkcoin/base58"
+)
+
+func TestCreateTransactionWithRemoteSigner(t *testing.T) {
+	server := httptest.NewServer(SignerHandler(NewPassphraseSigner("this is a top secret passphrase"), ""))
+	defer server.Close()
+
+	signer, err := NewRemoteSigner(server.URL, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx, err := CreateTransactionWithSigner(testRecipient(), 133380000000, "remote signed", signer, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if tx.SenderPublicKey != "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192" {
+		t.Error("Wrong Public Key")
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	log.Println(t.Name(), "Success")
+}
+
+func TestRemoteSignerUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "signer.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go http.Serve(listener, SignerHandler(NewPassphraseSigner("second top secret"), ""))
+
+	secondSigner, err := NewRemoteSigner("unix://"+socket, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx, err := CreateVoteWithSigner("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", NewPassphraseSigner("this is a top secret passphrase"), secondSigner)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = tx.SecondVerify(); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRemoteSignerInvalidHash(t *testing.T) {
+	server := httptest.NewServer(SignerHandler(NewPassphraseSigner("this is a top secret passphrase"), ""))
+	defer server.Close()
+
+	signer, err := NewRemoteSigner(server.URL, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = signer.SignHash([]byte{1, 2, 3}); err == nil {
+		t.Error("short hash signed")
+	} else if _, ok := err.(RemoteSignerError); !ok {
+		t.Error("expected RemoteSignerError, got", err)
+	}
+}
+
+func TestRemoteSignerToken(t *testing.T) {
+	server := httptest.NewServer(SignerHandler(NewPassphraseSigner("this is a top secret passphrase"), "shared secret"))
+	defer server.Close()
+
+	if _, err := NewRemoteSigner(server.URL, ""); err == nil {
+		t.Error("Connected without token")
+	}
+	if _, err := NewRemoteSigner(server.URL, "wrong secret"); err == nil {
+		t.Error("Connected with wrong token")
+	}
+	resp, err := http.Post(server.URL+"/sign", "application/json", strings.NewReader(`{"hash":"`+strings.Repeat("00", 32)+`"}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Error("Hash signed without token", resp.StatusCode)
+	}
+
+	signer, err := NewRemoteSigner(server.URL, "shared secret")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx, err := CreateTransactionWithSigner(testRecipient(), 133380000000, "remote signed", signer, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRemoteSignerTimeout(t *testing.T) {
+	handler := SignerHandler(NewPassphraseSigner("this is a top secret passphrase"), "")
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sign" {
+			<-stalled
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	timeout := RemoteSignerTimeout
+	RemoteSignerTimeout = 100 * time.Millisecond
+	defer func() {
+		RemoteSignerTimeout = timeout
+	}()
+	signer, err := NewRemoteSigner(server.URL, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = signer.SignHash(make([]byte, 32)); err == nil {
+		t.Error("Stalled signer returned signature")
+	}
+	log.Println(t.Name(), err)
+}
+
+//uncompressedWIF returns private key of passphrase in uncompressed WIF format
+func uncompressedWIF(passphrase string) string {
+	key := arkcoin.NewPrivateKeyFromPassword(passphrase, arkcoin.ActiveCoinConfig)
+	return base58.Encode(append([]byte{arkcoin.ActiveCoinConfig.DumpedPrivateKeyHeader[0]}, key.Serialize()...))
+}
+
+func TestUncompressedWIF(t *testing.T) {
+	wif := uncompressedWIF("this is a top secret passphrase")
+	if _, err := NewWIFSigner(wif); err != ErrWIFUncompressed {
+		t.Error("Uncompressed WIF accepted", err)
+	}
+
+	key, err := arkcoin.FromWIF(wif, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	signer := NewKeySigner(key)
+	if hex.EncodeToString(signer.PublicKey()) != "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192" {
+		t.Error("Public key not compressed", hex.EncodeToString(signer.PublicKey()))
+	}
+	tx, err := CreateTransactionWithSigner(testRecipient(), 1, "", signer, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	txBytes, _ := tx.toBytes(false, false)
+	if parsed, err := FromBytes(txBytes); err != nil || parsed.ID != tx.ID {
+		t.Error("Transaction not parsed", err)
+	}
+}
+
+func TestCreateFromKey(t *testing.T) {
+	key, err := arkcoin.FromWIF(arkcoin.NewPrivateKeyFromPassword("this is a top secret passphrase", arkcoin.ActiveCoinConfig).WIFAddress(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	secondKey := arkcoin.NewPrivateKeyFromPassword("second passphrase", arkcoin.ActiveCoinConfig)
+
+	tx, err := CreateTransactionFromKey(testRecipient(), 133380000000, "signed with key", key, secondKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	passTx, err := CreateTransaction(testRecipient(), 133380000000, "signed with key", "this is a top secret passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if tx.SenderPublicKey != passTx.SenderPublicKey || tx.Signature != passTx.Signature || tx.SignSignature != passTx.SignSignature {
+		t.Error("Key and passphrase signatures differ", tx.ToJSON(), passTx.ToJSON())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = tx.SecondVerify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	delegateKey := hex.EncodeToString(NewPassphraseSigner("delegate").PublicKey())
+	vote, err := CreateVoteFromKey("+", delegateKey, key, nil)
+	if err != nil || vote.SignSignature != "" || vote.Verify() != nil {
+		t.Error("Wrong vote", err)
+	}
+	second, err := CreateSecondSignatureFromKey(key, secondKey)
+	if err != nil || second.Asset["signature"] != hex.EncodeToString(secondKey.PublicKey.Serialize()) || second.Verify() != nil {
+		t.Error("Wrong second signature", err)
+	}
+
+	built, err := NewTransactionBuilder(SENDARK).Recipient(testRecipient()).Amount(1).Key(key, secondKey).Build()
+	if err != nil || built.SecondVerify() != nil {
+		t.Error("Wrong built transaction", err)
+	}
+	log.Println(t.Name(), "Success")
+}
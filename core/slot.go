@@ -31,3 +31,8 @@ func GetTransactionTime(timestamp int32) time.Time {
 
 	return timeCalculcated
 }
+
+//GetTimestamp returns Ark epoch timestamp of t, inverse of GetTransactionTime
+func GetTimestamp(t time.Time) int32 {
+	return int32(t.Sub(mainNetStart).Seconds())
+}
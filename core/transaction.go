@@ -6,8 +6,11 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/kristjank/ark-go/arkcoin"
 	"github.com/kristjank/ark-go/arkcoin/base58"
@@ -23,6 +26,10 @@ const (
 	CREATEDELEGATE  = 2
 	VOTE            = 3
 	MULTISIGNATURE  = 4
+	MULTIPAYMENT    = 6 //type number as in AIP-11
+	HTLCLOCK        = 8 //AIP-11 only
+	HTLCCLAIM       = 9
+	HTLCREFUND      = 10
 )
 
 //Transaction struct - represents structure of ARK.io blockchain transaction
@@ -30,6 +37,11 @@ const (
 //Empty fields are emmited by default
 type Transaction struct {
 	ID                    string            `json:"id,omitempty"`
+	Version               byte              `json:"version,omitempty"`   //0 or 1 legacy, 2 AIP-11
+	Network               byte              `json:"network,omitempty"`   //AIP-11 network (address version) byte
+	TypeGroup             uint32            `json:"typeGroup,omitempty"` //AIP-11 only
+	Nonce                 uint64            `json:"nonce,string,omitempty"`
+	Expiration            uint32            `json:"expiration,omitempty"` //AIP-11 transfer expiration height, 0 for none
 	Timestamp             int32             `json:"timestamp,omitempty"`
 	RecipientID           string            `json:"recipientId,omitempty"`
 	Amount                int64             `json:"amount,omitempty"`
@@ -39,6 +51,8 @@ type Transaction struct {
 	VendorField           string            `json:"vendorField,omitempty"`
 	Signature             string            `json:"signature,omitempty"`
 	SignSignature         string            `json:"signSignature,omitempty"`
+	Signatures            []string          `json:"signatures,omitempty"` //multisignature keysgroup signatures
+	Payments              []Payment         `json:"payments,omitempty"`   //multipayment recipients and amounts
 	SenderPublicKey       string            `json:"senderPublicKey,omitempty"`
 	SecondSenderPublicKey string            `json:"secondSenderPublicKey,omitempty"`
 	RequesterPublicKey    string            `json:"requesterPublicKey,omitempty"`
@@ -48,33 +62,196 @@ type Transaction struct {
 	Confirmations         int               `json:"confirmations,omitempty"`
 }
 
-//FromBytes implementation - deserialization of recveived tx
-func fromBytes(txbytes []byte) Transaction {
-	txReader := bytes.NewReader(txbytes)
+//transaction header length - type, timestamp, sender public key, recipient, vendor field, amount and fee
+const txHeaderLength = 1 + 4 + 33 + 21 + 64 + 8 + 8
 
-	tx := Transaction{}
+//length of a vote ("+" or "-" and hex public key) and of a multisignature keysgroup member ("+" and hex public key)
+const txVoteLength = 1 + 66
 
-	//Get Transaction Type
-	binary.Read(txReader, binary.LittleEndian, &tx.Type)
+var (
+	//ErrTransactionTruncated is returned by FromBytes when serialized transaction ends too early
+	ErrTransactionTruncated = errors.New("serialized transaction is truncated")
+	//ErrTransactionFormat is returned by FromBytes for malformed assets or signatures, or trailing bytes
+	ErrTransactionFormat = errors.New("serialized transaction is malformed")
+	//ErrTransactionType is returned by FromBytes for unknown transaction types
+	ErrTransactionType = errors.New("unknown transaction type")
+)
 
-	//GetTimeStamp from 	binary.Write(txBuf, binary.LittleEndian, uint32(tx.Timestamp))
-	binary.Read(txReader, binary.LittleEndian, &tx.Timestamp)
+//FromBytes deserializes transaction serialized by the Ark protocol (as signed and hashed for the id),
+//including assets, signature and second signature. ID is recalculated for signed transactions.
+//Both legacy and AIP-11 (v2) serialization is accepted.
+//SecondSenderPublicKey is not serialized, set it before calling SecondVerify.
+func FromBytes(txbytes []byte) (*Transaction, error) {
+	if len(txbytes) > 0 && txbytes[0] == txV2Header {
+		return fromBytesV2(txbytes)
+	}
+	if len(txbytes) < txHeaderLength {
+		return nil, ErrTransactionTruncated
+	}
+	tx := &Transaction{Type: txbytes[0]}
+	tx.Timestamp = int32(binary.LittleEndian.Uint32(txbytes[1:5]))
+	tx.SenderPublicKey = hex.EncodeToString(txbytes[5:38])
+
+	if recipient := txbytes[38:59]; !bytes.Equal(recipient, make([]byte, 21)) {
+		tx.RecipientID = base58.Encode(recipient)
+	}
+	tx.VendorField = string(bytes.TrimRight(txbytes[59:123], "\x00"))
+	tx.Amount = int64(binary.LittleEndian.Uint64(txbytes[123:131]))
+	tx.Fee = int64(binary.LittleEndian.Uint64(txbytes[131:139]))
 
-	//Get SenderPublicKey
-	byteKey := make([]byte, 33)
-	binary.Read(txReader, binary.LittleEndian, byteKey)
-	tx.SenderPublicKey = hex.EncodeToString(byteKey)
+	rest := txbytes[txHeaderLength:]
+	var err error
+	switch tx.Type {
+	case SENDARK:
+	case SECONDSIGNATURE:
+		if len(rest) < 33 {
+			return nil, ErrTransactionTruncated
+		}
+		tx.Asset = map[string]string{"signature": hex.EncodeToString(rest[:33])}
+		rest = rest[33:]
+	case CREATEDELEGATE:
+		var username string
+		username, rest = splitDelegateUsername(rest)
+		if !isDelegateUsername(username) {
+			return nil, ErrTransactionFormat
+		}
+		tx.Asset = map[string]string{"username": username}
+	case VOTE:
+		var votes []string
+		if votes, rest, err = splitVotes(rest, "+-"); err != nil {
+			return nil, err
+		}
+		if len(votes) == 0 {
+			return nil, ErrTransactionFormat
+		}
+		tx.Asset = map[string]string{"votes": strings.Join(votes, "")}
+	case MULTISIGNATURE:
+		if len(rest) < 2 {
+			return nil, ErrTransactionTruncated
+		}
+		min, lifetime := rest[0], rest[1]
+		var keysgroup []string
+		if keysgroup, rest, err = splitVotes(rest[2:], "+"); err != nil {
+			return nil, err
+		}
+		if len(keysgroup) == 0 {
+			return nil, ErrTransactionFormat
+		}
+		tx.Asset = map[string]string{
+			"min":       strconv.Itoa(int(min)),
+			"lifetime":  strconv.Itoa(int(lifetime)),
+			"keysgroup": strings.Join(keysgroup, ","),
+		}
+	default:
+		return nil, ErrTransactionType
+	}
 
-	return tx
+	if err = tx.readSignatures(rest); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+//readSignatures reads optional signature and second signature following the assets and calculates the id
+func (tx *Transaction) readSignatures(rest []byte) error {
+	sig, rest, err := splitDERSignature(rest)
+	if err != nil {
+		return err
+	}
+	var signSig []byte
+	if signSig, rest, err = splitDERSignature(rest); err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return ErrTransactionFormat
+	}
+	if len(sig) > 0 {
+		tx.Signature = hex.EncodeToString(sig)
+		if len(signSig) > 0 {
+			tx.SignSignature = hex.EncodeToString(signSig)
+		}
+		return tx.getID()
+	}
+	return nil
+}
+
+//splitDERSignature returns DER sequence at the start of b and the remaining bytes.
+//Empty b has no signature. Strict DER rules are checked by Verify, not here.
+func splitDERSignature(b []byte) ([]byte, []byte, error) {
+	if len(b) == 0 {
+		return nil, b, nil
+	}
+	if b[0] != 0x30 {
+		return nil, nil, ErrTransactionFormat
+	}
+	if len(b) > 1 && b[1] >= 0x80 {
+		return nil, nil, ErrTransactionFormat
+	}
+	if len(b) < 2 || len(b) < int(b[1])+2 {
+		return nil, nil, ErrTransactionTruncated
+	}
+	return b[:b[1]+2], b[b[1]+2:], nil
+}
+
+//splitVotes reads votes or keysgroup members, each starting with one of prefixes, until signature starts
+func splitVotes(b []byte, prefixes string) ([]string, []byte, error) {
+	var votes []string
+	for len(b) > 0 && strings.IndexByte(prefixes, b[0]) >= 0 {
+		if len(b) < txVoteLength {
+			return nil, nil, ErrTransactionTruncated
+		}
+		if _, err := hex.DecodeString(string(b[1:txVoteLength])); err != nil {
+			return nil, nil, ErrTransactionFormat
+		}
+		votes = append(votes, string(b[:txVoteLength]))
+		b = b[txVoteLength:]
+	}
+	return votes, b, nil
+}
+
+//splitDelegateUsername splits delegate username from following signatures.
+//Username can contain '0' (0x30, DER sequence tag), but never 0x02, the DER integer tag following the sequence length.
+func splitDelegateUsername(b []byte) (string, []byte) {
+	for i := 0; i < len(b); i++ {
+		if b[i] == 0x30 && i+2 < len(b) && b[i+2] == 0x02 {
+			return string(b[:i]), b[i:]
+		}
+	}
+	return string(b), nil
+}
+
+//isDelegateUsername reports whether username has 1-20 lower case letters, digits or !@$&_. characters
+func isDelegateUsername(username string) bool {
+	if len(username) == 0 || len(username) > 20 {
+		return false
+	}
+	for _, c := range username {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.ContainsRune("!@$&_.", c)) {
+			return false
+		}
+	}
+	return true
 }
 
 //ToBytes returns bytearray of the Transaction object to be signed and send to blockchain
-func (tx *Transaction) toBytes(skipSignature, skipSecondSignature bool) []byte {
+//AIP-11 serialization is used for transactions with Version 2.
+func (tx *Transaction) toBytes(skipSignature, skipSecondSignature bool) ([]byte, error) {
+	if tx.Version >= TransactionV2 {
+		return tx.toBytesV2(skipSignature, skipSecondSignature)
+	}
+	//multipayment and HTLC transactions exist in AIP-11 serialization only
+	if tx.Type == MULTIPAYMENT || tx.Type >= HTLCLOCK {
+		return nil, ErrTransactionType
+	}
 	txBuf := new(bytes.Buffer)
 	binary.Write(txBuf, binary.LittleEndian, tx.Type)
 	binary.Write(txBuf, binary.LittleEndian, uint32(tx.Timestamp))
 
-	binary.Write(txBuf, binary.LittleEndian, quickHexDecode(tx.SenderPublicKey))
+	senderPublicKey, err := quickHexDecode("sender public key", tx.SenderPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	binary.Write(txBuf, binary.LittleEndian, senderPublicKey)
 
 	if tx.RequesterPublicKey != "" {
 		res, err := base58.Decode(tx.RequesterPublicKey)
@@ -86,7 +263,7 @@ func (tx *Transaction) toBytes(skipSignature, skipSecondSignature bool) []byte {
 	if tx.RecipientID != "" {
 		res, err := base58.Decode(tx.RecipientID)
 		if err != nil {
-			log.Fatal("Error converting Decoding b58 ", err.Error())
+			return nil, fmt.Errorf("recipient %s: %s", tx.RecipientID, err.Error())
 		}
 		binary.Write(txBuf, binary.LittleEndian, res)
 	} else {
@@ -95,12 +272,13 @@ func (tx *Transaction) toBytes(skipSignature, skipSecondSignature bool) []byte {
 
 	if tx.VendorField != "" {
 		vendorBytes := []byte(tx.VendorField)
-		if len(vendorBytes) < 65 {
-			binary.Write(txBuf, binary.LittleEndian, vendorBytes)
-
-			bs := make([]byte, 64-len(vendorBytes))
-			binary.Write(txBuf, binary.LittleEndian, bs)
+		if len(vendorBytes) > arkcoin.MaxVendorFieldLength {
+			return nil, ErrVendorFieldTooLong
 		}
+		binary.Write(txBuf, binary.LittleEndian, vendorBytes)
+
+		bs := make([]byte, 64-len(vendorBytes))
+		binary.Write(txBuf, binary.LittleEndian, bs)
 	} else {
 		binary.Write(txBuf, binary.LittleEndian, make([]byte, 64))
 	}
@@ -110,28 +288,50 @@ func (tx *Transaction) toBytes(skipSignature, skipSecondSignature bool) []byte {
 
 	switch tx.Type {
 	case SECONDSIGNATURE:
-		binary.Write(txBuf, binary.LittleEndian, quickHexDecode(tx.Asset["signature"]))
+		secondPublicKey, err := quickHexDecode("second signature public key", tx.Asset["signature"])
+		if err != nil {
+			return nil, err
+		}
+		binary.Write(txBuf, binary.LittleEndian, secondPublicKey)
 	case CREATEDELEGATE:
 		usernameBytes := []byte(tx.Asset["username"])
 		binary.Write(txBuf, binary.LittleEndian, usernameBytes)
 	case VOTE:
 		voteBytes := []byte(tx.Asset["votes"])
 		binary.Write(txBuf, binary.LittleEndian, voteBytes)
+	case MULTISIGNATURE:
+		min, err := strconv.Atoi(tx.Asset["min"])
+		if err != nil {
+			return nil, fmt.Errorf("multisignature min: %s", err.Error())
+		}
+		lifetime, err := strconv.Atoi(tx.Asset["lifetime"])
+		if err != nil {
+			return nil, fmt.Errorf("multisignature lifetime: %s", err.Error())
+		}
+		binary.Write(txBuf, binary.LittleEndian, byte(min))
+		binary.Write(txBuf, binary.LittleEndian, byte(lifetime))
+		binary.Write(txBuf, binary.LittleEndian, []byte(strings.Replace(tx.Asset["keysgroup"], ",", "", -1)))
 	}
 
-	if !skipSignature && len(tx.Signature) > 0 {
-		binary.Write(txBuf, binary.LittleEndian, quickHexDecode(tx.Signature))
-	}
-
-	if !skipSecondSignature && len(tx.SignSignature) > 0 {
-		binary.Write(txBuf, binary.LittleEndian, quickHexDecode(tx.SignSignature))
+	if err := tx.writeSignatures(txBuf, skipSignature, skipSecondSignature); err != nil {
+		return nil, err
 	}
-
-	return txBuf.Bytes()
+	return txBuf.Bytes(), nil
 }
 
 //CreateTransaction creates and returns new Transaction struct...
-func CreateTransaction(recipientID string, satoshiAmount int64, vendorField, passphrase, secondPassphrase string) *Transaction {
+//recipientID is validated for the active network before anything is signed
+func CreateTransaction(recipientID string, satoshiAmount int64, vendorField, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateTransactionWithSigner(recipientID, satoshiAmount, vendorField, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateTransactionWithSigner creates transfer Transaction signed by signer.
+//secondSigner is needed only for accounts with second signature, nil otherwise.
+func CreateTransactionWithSigner(recipientID string, satoshiAmount int64, vendorField string, signer, secondSigner Signer) (*Transaction, error) {
+	if err := arkcoin.ValidateAddress(recipientID, arkcoin.ActiveCoinConfig); err != nil {
+		return nil, err
+	}
+
 	tx := Transaction{
 		Type:        SENDARK,
 		RecipientID: recipientID,
@@ -139,45 +339,52 @@ func CreateTransaction(recipientID string, satoshiAmount int64, vendorField, pas
 		Fee:         EnvironmentParams.Fees.Send,
 		VendorField: vendorField,
 	}
+	return tx.signAll(signer, secondSigner)
+}
 
-	tx.Timestamp = GetTime() //1
-	tx.sign(passphrase)
-
-	if len(secondPassphrase) > 0 {
-		tx.secondSign(secondPassphrase)
-	}
-
-	tx.getID() //calculates id of transaction
-	return &tx
+//CreateTransactionFromKey creates transfer Transaction signed with private key.
+//secondKey is needed only for accounts with second signature, nil otherwise.
+func CreateTransactionFromKey(recipientID string, satoshiAmount int64, vendorField string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateTransactionWithSigner(recipientID, satoshiAmount, vendorField, NewKeySigner(key), secondKeySigner(secondKey))
 }
 
 //CreateVote transaction used to vote for a chosen Delegate
 //if updown value = "+" vot is given to the specified PublicKey
 //if updown value = "-" vot is taken from the specified PublicKey
-func CreateVote(updown, delegatePubKey, passphrase, secondPassphrase string) *Transaction {
+func CreateVote(updown, delegatePubKey, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateVoteWithSigner(updown, delegatePubKey, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateVoteWithSigner creates vote Transaction signed by signer, see CreateVote
+func CreateVoteWithSigner(updown, delegatePubKey string, signer, secondSigner Signer) (*Transaction, error) {
 	tx := Transaction{
 		Type:        VOTE,
 		Fee:         EnvironmentParams.Fees.Vote,
 		VendorField: "Delegate vote transaction",
 		Asset:       make(map[string]string),
 	}
-	key := arkcoin.NewPrivateKeyFromPassword(passphrase, arkcoin.ActiveCoinConfig)
-	tx.RecipientID = key.PublicKey.Address()
+	key, err := arkcoin.NewPublicKey(signer.PublicKey(), arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return nil, err
+	}
+	tx.RecipientID = key.Address()
 
 	tx.Asset["votes"] = updown + delegatePubKey
-	tx.Timestamp = GetTime() //1
-	tx.sign(passphrase)
-
-	if len(secondPassphrase) > 0 {
-		tx.secondSign(secondPassphrase)
-	}
+	return tx.signAll(signer, secondSigner)
+}
 
-	tx.getID() //calculates id of transaction
-	return &tx
+//CreateVoteFromKey creates vote Transaction signed with private key, see CreateVote
+func CreateVoteFromKey(updown, delegatePubKey string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateVoteWithSigner(updown, delegatePubKey, NewKeySigner(key), secondKeySigner(secondKey))
 }
 
 //CreateDelegate creates and returns new Transaction struct...
-func CreateDelegate(username, passphrase, secondPassphrase string) *Transaction {
+func CreateDelegate(username, passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateDelegateWithSigner(username, NewPassphraseSigner(passphrase), secondPassphraseSigner(secondPassphrase))
+}
+
+//CreateDelegateWithSigner creates delegate registration Transaction signed by signer
+func CreateDelegateWithSigner(username string, signer, secondSigner Signer) (*Transaction, error) {
 	tx := Transaction{
 		Type:        CREATEDELEGATE,
 		Fee:         EnvironmentParams.Fees.Delegate,
@@ -185,19 +392,22 @@ func CreateDelegate(username, passphrase, secondPassphrase string) *Transaction
 		Asset:       make(map[string]string),
 	}
 	tx.Asset["username"] = username
-	tx.Timestamp = GetTime() //1
-	tx.sign(passphrase)
-
-	if len(secondPassphrase) > 0 {
-		tx.secondSign(secondPassphrase)
-	}
+	return tx.signAll(signer, secondSigner)
+}
 
-	tx.getID() //calculates id of transaction
-	return &tx
+//CreateDelegateFromKey creates delegate registration Transaction signed with private key
+func CreateDelegateFromKey(username string, key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateDelegateWithSigner(username, NewKeySigner(key), secondKeySigner(secondKey))
 }
 
 //CreateSecondSignature creates and returns new Transaction struct...
-func CreateSecondSignature(passphrase, secondPassphrase string) *Transaction {
+func CreateSecondSignature(passphrase, secondPassphrase string) (*Transaction, error) {
+	return CreateSecondSignatureWithSigner(NewPassphraseSigner(passphrase), NewPassphraseSigner(secondPassphrase))
+}
+
+//CreateSecondSignatureWithSigner creates Transaction registering secondSigner public key as second signature.
+//The transaction is signed by signer only.
+func CreateSecondSignatureWithSigner(signer, secondSigner Signer) (*Transaction, error) {
 	tx := Transaction{
 		Type:        SECONDSIGNATURE,
 		Fee:         EnvironmentParams.Fees.SecondSignature,
@@ -205,92 +415,218 @@ func CreateSecondSignature(passphrase, secondPassphrase string) *Transaction {
 		Asset:       make(map[string]string),
 	}
 
-	key := arkcoin.NewPrivateKeyFromPassword(secondPassphrase, arkcoin.ActiveCoinConfig)
-	tx.Asset["signature"] = hex.EncodeToString(key.PublicKey.Serialize())
+	tx.Asset["signature"] = hex.EncodeToString(secondSigner.PublicKey())
+	return tx.signAll(signer, nil)
+}
+
+//CreateSecondSignatureFromKey creates Transaction registering public key of secondKey as second signature,
+//signed with key
+func CreateSecondSignatureFromKey(key, secondKey *arkcoin.PrivateKey) (*Transaction, error) {
+	return CreateSecondSignatureWithSigner(NewKeySigner(key), NewKeySigner(secondKey))
+}
+
+//secondPassphraseSigner returns nil Signer for empty second passphrase
+func secondPassphraseSigner(secondPassphrase string) Signer {
+	if len(secondPassphrase) == 0 {
+		return nil
+	}
+	return NewPassphraseSigner(secondPassphrase)
+}
+
+//secondKeySigner returns nil Signer for nil second key
+func secondKeySigner(secondKey *arkcoin.PrivateKey) Signer {
+	if secondKey == nil {
+		return nil
+	}
+	return NewKeySigner(secondKey)
+}
+
+//signAll sets timestamp, signs with signer and optional secondSigner and calculates the id.
+//On AIP-11 networks version, network, type group and the next sender nonce (from Nonces) are set too.
+func (tx *Transaction) signAll(signer, secondSigner Signer) (*Transaction, error) {
+	if err := tx.prepareSign(signer.PublicKey()); err != nil {
+		return nil, err
+	}
+	if err := tx.signPrepared(signer, secondSigner); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+//prepareSign sets timestamp and AIP-11 fields of signAll for sender. Nonces are handed out in call order.
+func (tx *Transaction) prepareSign(senderPublicKey []byte) error {
 	tx.Timestamp = GetTime() //1
-	tx.sign(passphrase)
+	if EnvironmentParams.Network.TransactionVersion >= TransactionV2 {
+		nonce, err := Nonces.Next(hex.EncodeToString(senderPublicKey))
+		if err != nil {
+			return err
+		}
+		tx.Version, tx.Network, tx.TypeGroup, tx.Nonce = TransactionV2, EnvironmentParams.Network.AddressVersion, CoreTypeGroup, nonce
+		if !hasVendorField(tx.Type) {
+			tx.VendorField = ""
+		}
+	}
+	return nil
+}
 
-	tx.getID() //calculates id of transaction
-	return &tx
+//signPrepared signs transaction prepared by prepareSign and calculates the id, it is safe for concurrent use
+//on different transactions
+func (tx *Transaction) signPrepared(signer, secondSigner Signer) error {
+	if err := tx.sign(signer); err != nil {
+		return err
+	}
+
+	if secondSigner != nil {
+		if err := tx.secondSign(secondSigner); err != nil {
+			return err
+		}
+	}
+
+	return tx.getID() //calculates id of transaction
 }
 
 //Sign the Transaction
-func (tx *Transaction) sign(passphrase string) {
-	key := arkcoin.NewPrivateKeyFromPassword(passphrase, arkcoin.ActiveCoinConfig)
-
-	tx.SenderPublicKey = hex.EncodeToString(key.PublicKey.Serialize())
+func (tx *Transaction) sign(signer Signer) error {
+	tx.SenderPublicKey = hex.EncodeToString(signer.PublicKey())
 
+	txBytes, err := tx.toBytes(true, true)
+	if err != nil {
+		return err
+	}
 	trHashBytes := sha256.New()
-	trHashBytes.Write(tx.toBytes(true, true))
+	trHashBytes.Write(txBytes)
 
-	sig, err := key.Sign(trHashBytes.Sum(nil))
-	if err == nil {
-		tx.Signature = hex.EncodeToString(sig)
+	sig, err := signer.SignHash(trHashBytes.Sum(nil))
+	if err != nil {
+		return err
 	}
+	tx.Signature = hex.EncodeToString(sig)
+	return nil
 }
 
 //SecondSign the Transaction
-func (tx *Transaction) secondSign(passphrase string) {
-	key := arkcoin.NewPrivateKeyFromPassword(passphrase, arkcoin.ActiveCoinConfig)
-
-	tx.SecondSenderPublicKey = hex.EncodeToString(key.PublicKey.Serialize())
+func (tx *Transaction) secondSign(signer Signer) error {
+	tx.SecondSenderPublicKey = hex.EncodeToString(signer.PublicKey())
+	txBytes, err := tx.toBytes(false, true)
+	if err != nil {
+		return err
+	}
 	trHashBytes := sha256.New()
-	trHashBytes.Write(tx.toBytes(false, true))
+	trHashBytes.Write(txBytes)
 
-	sig, err := key.Sign(trHashBytes.Sum(nil))
-	if err == nil {
-		tx.SignSignature = hex.EncodeToString(sig)
+	sig, err := signer.SignHash(trHashBytes.Sum(nil))
+	if err != nil {
+		return err
 	}
+	tx.SignSignature = hex.EncodeToString(sig)
+	return nil
 }
 
 //GetID returns calculated ID of trancation - hashed s256
-func (tx *Transaction) getID() {
+func (tx *Transaction) getID() error {
+	txBytes, err := tx.toBytes(false, false)
+	if err != nil {
+		return err
+	}
 	trHashBytes := sha256.New()
-	trHashBytes.Write(tx.toBytes(false, false))
+	trHashBytes.Write(txBytes)
 
 	tx.ID = hex.EncodeToString(trHashBytes.Sum(nil))
+	return nil
 }
 
-//ToJSON converts transaction object to JSON string
+//ToJSON converts transaction object to JSON string.
+//Transaction fields always marshal, empty string is returned only if that ever changes.
 func (tx *Transaction) ToJSON() string {
 	txJSON, err := json.Marshal(tx)
 	if err != nil {
-		log.Fatal(err.Error())
+		return ""
 	}
 	return string(txJSON)
 }
 
-func quickHexDecode(data string) []byte {
+//quickHexDecode decodes hex transaction field, errors name the field
+func quickHexDecode(field, data string) ([]byte, error) {
 	res, err := hex.DecodeString(data)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("%s: %s", field, err.Error())
+	}
+	return res, nil
+}
+
+//writeSignatures appends signature and second signature, unless skipped or empty
+func (tx *Transaction) writeSignatures(txBuf *bytes.Buffer, skipSignature, skipSecondSignature bool) error {
+	if !skipSignature && len(tx.Signature) > 0 {
+		sig, err := quickHexDecode("signature", tx.Signature)
+		if err != nil {
+			return err
+		}
+		binary.Write(txBuf, binary.LittleEndian, sig)
+	}
+
+	if !skipSecondSignature && len(tx.SignSignature) > 0 {
+		sig, err := quickHexDecode("second signature", tx.SignSignature)
+		if err != nil {
+			return err
+		}
+		binary.Write(txBuf, binary.LittleEndian, sig)
 	}
-	return res
+	return nil
 }
 
 //Verify function verifies if tx is validly signed
 //if return == nill verification was succesfull
+//Signatures must be strict low-S DER, as transactions can be received from other peers
 func (tx *Transaction) Verify() error {
-	key, err := arkcoin.NewPublicKey(quickHexDecode(tx.SenderPublicKey), arkcoin.ActiveCoinConfig)
+	txBytes, err := tx.toBytes(true, true)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
-	trHashBytes := sha256.New()
-	trHashBytes.Write(tx.toBytes(true, true))
-	return key.Verify(quickHexDecode(tx.Signature), trHashBytes.Sum(nil))
-
+	return verifySignature(tx.SenderPublicKey, tx.Signature, txBytes)
 }
 
 //SecondVerify function verifies if tx is validly signed
 //if return == nill verification was succesfull
+//Signatures must be strict low-S DER, as transactions can be received from other peers
 func (tx *Transaction) SecondVerify() error {
-	key, err := arkcoin.NewPublicKey(quickHexDecode(tx.SecondSenderPublicKey), arkcoin.ActiveCoinConfig)
+	txBytes, err := tx.toBytes(false, true)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
+	}
+	return verifySignature(tx.SecondSenderPublicKey, tx.SignSignature, txBytes)
+}
+
+//verifySignature verifies hex signature of txBytes sha256 hash by hex publicKey
+func verifySignature(publicKey, signature string, txBytes []byte) error {
+	keyBytes, err := quickHexDecode("public key", publicKey)
+	if err != nil {
+		return err
+	}
+	key, err := arkcoin.NewPublicKey(keyBytes, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return err
+	}
+	sig, err := quickHexDecode("signature", signature)
+	if err != nil {
+		return err
 	}
 	trHashBytes := sha256.New()
-	trHashBytes.Write(tx.toBytes(false, true))
-	return key.Verify(quickHexDecode(tx.SignSignature), trHashBytes.Sum(nil))
+	trHashBytes.Write(txBytes)
+	return key.VerifyStrict(sig, trHashBytes.Sum(nil))
+}
+
+//DecryptMemo decrypts vendor field encrypted with arkcoin.EncryptMemo by the transaction sender.
+//key is the recipient private key.
+func (tx *Transaction) DecryptMemo(key *arkcoin.PrivateKey) (string, error) {
+	senderKey, err := hex.DecodeString(tx.SenderPublicKey)
+	if err != nil {
+		return "", err
+	}
+	sender, err := arkcoin.NewPublicKey(senderKey, arkcoin.ActiveCoinConfig)
+	if err != nil {
+		return "", err
+	}
+	return arkcoin.DecryptMemo(key, sender, tx.VendorField)
 }
 
 //PostTransactionResponse structure for call /peer/list
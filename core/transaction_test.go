@@ -1,7 +1,9 @@
 package core
 
 import (
+	"encoding/hex"
 	"log"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,10 +11,13 @@ import (
 )
 
 func TestCreateSignTransaction(t *testing.T) {
-	tx := CreateTransaction("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25",
+	tx, err := CreateTransaction(testRecipient(),
 		133380000000,
 		"This is first transaction from ARK-NET",
 		"this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
 	if tx.Amount == 0 {
 		t.Error("Amount is zero")
@@ -39,12 +44,15 @@ func TestCreateSignTransaction(t *testing.T) {
 }
 
 func TestVerifyTransaction(t *testing.T) {
-	tx := CreateTransaction("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25",
+	tx, err := CreateTransaction(testRecipient(),
 		133380000000,
 		"This is first transaction from ARK-NET",
 		"this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	err := tx.Verify()
+	err = tx.Verify()
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -52,18 +60,43 @@ func TestVerifyTransaction(t *testing.T) {
 }
 
 func TestSecondVerifyTransaction(t *testing.T) {
-	tx := CreateTransaction("AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25",
+	tx, err := CreateTransaction(testRecipient(),
 		133380000000,
 		"This is first transaction from ARK-NET",
 		"this is a top secret passphrase", "second top secret")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	err := tx.SecondVerify()
+	err = tx.SecondVerify()
 	if err != nil {
 		t.Error(err.Error())
 	}
 	log.Println(t.Name(), "Success")
 }
 
+func TestTransactionEncryptedMemo(t *testing.T) {
+	sender := arkcoin.NewPrivateKeyFromPassword("this is a top secret passphrase", arkcoin.ActiveCoinConfig)
+	recipient := arkcoin.NewPrivateKeyFromPassword("second top secret", arkcoin.ActiveCoinConfig)
+	memo, err := arkcoin.EncryptMemo(sender, recipient.PublicKey, "payout 2017-11 #42")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tx, err := CreateTransaction(recipient.PublicKey.Address(), 100000000, memo, "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	decrypted, err := tx.DecryptMemo(recipient)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if decrypted != "payout 2017-11 #42" {
+		t.Error("Wrong memo", decrypted)
+	}
+	log.Println(t.Name(), "Success, vendor field: ", tx.VendorField)
+}
+
 func TestPostTransaction(t *testing.T) {
 	arkapi := NewArkClient(nil)
 
@@ -76,20 +109,29 @@ func TestPostTransaction(t *testing.T) {
 		recepient := "DFTzLwEHKKn3VGce6vZSueEmoPWpEZswhB"
 		passphrase := "outer behind tray slice trash cave table divert wild buddy snap news"
 
-		tx := CreateTransaction(recepient,
+		tx, err := CreateTransaction(recepient,
 			1,
 			"1ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
-		tx1 := CreateTransaction(recepient,
+		tx1, err := CreateTransaction(recepient,
 			1,
 			"2ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
-		tx2 := CreateTransaction(recepient,
+		tx2, err := CreateTransaction(recepient,
 			1,
 			"3ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
 		var payload TransactionPayload
 		payload.Transactions = append(payload.Transactions, tx)
@@ -180,9 +222,12 @@ func TestGetTransactionUnconfirmed(t *testing.T) {
 }
 
 func TestCreateDelegate(t *testing.T) {
-	tx := CreateDelegate("chris", "this is a top secret passphrase", "")
+	tx, err := CreateDelegate("chris", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	err := tx.Verify()
+	err = tx.Verify()
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -191,9 +236,12 @@ func TestCreateDelegate(t *testing.T) {
 }
 
 func TestCreateVote(t *testing.T) {
-	tx := CreateVote("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", "this is a top secret passphrase", "")
+	tx, err := CreateVote("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	err := tx.Verify()
+	err = tx.Verify()
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -202,9 +250,12 @@ func TestCreateVote(t *testing.T) {
 }
 
 func TestCreateSecondSignature(t *testing.T) {
-	tx := CreateSecondSignature("this is a top secret passphrase", "this is new second passphrase")
+	tx, err := CreateSecondSignature("this is a top secret passphrase", "this is new second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	err := tx.Verify()
+	err = tx.Verify()
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -227,15 +278,102 @@ func TestAddress(t *testing.T) {
 }
 
 func TestFromBytes(t *testing.T) {
-	tx := CreateTransaction("D61mfSggzbvQgTUe6JhYKH2doHaqJ3Dyib", 1, "ARK-GOLang is saying whoop whooop", "passphrase", "")
+	transfer, err := CreateTransaction(testRecipient(), 1, "ARK-GOLang is saying whoop whooop", "passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	vote, err := CreateVote("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	delegate, err := CreateDelegate("a0b0.c", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	secondSignature, err := CreateSecondSignature("this is a top secret passphrase", "second top secret")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	keysgroup := []string{
+		"+" + hex.EncodeToString(NewPassphraseSigner("first").PublicKey()),
+		"+" + hex.EncodeToString(NewPassphraseSigner("second").PublicKey()),
+	}
+	multisig := &Transaction{
+		Type:  MULTISIGNATURE,
+		Fee:   EnvironmentParams.Fees.Send,
+		Asset: map[string]string{"min": "2", "lifetime": "24", "keysgroup": strings.Join(keysgroup, ",")},
+	}
+	if multisig, err = multisig.signAll(NewPassphraseSigner("this is a top secret passphrase"), nil); err != nil {
+		t.Fatal(err.Error())
+	}
 
-	//tx := CreateVote("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", "this is a top secret passphrase", "")
+	for _, tx := range []*Transaction{transfer, vote, delegate, secondSignature, multisig} {
+		txBytes, err := tx.toBytes(false, false)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		tx1, err := FromBytes(txBytes)
+		if err != nil {
+			t.Error(t.Name(), tx.Type, err.Error())
+			continue
+		}
+		//second public key is not serialized
+		tx1.SecondSenderPublicKey = tx.SecondSenderPublicKey
+		if tx1.ToJSON() != tx.ToJSON() {
+			t.Error("Deserialized transaction differs", tx1.ToJSON(), tx.ToJSON())
+		}
+		if err = tx1.Verify(); err != nil {
+			t.Error(err.Error())
+		}
+		if tx1.SignSignature != "" {
+			if err = tx1.SecondVerify(); err != nil {
+				t.Error(err.Error())
+			}
+		}
+	}
+	log.Println(t.Name(), "Success")
+}
 
-	tx1 := fromBytes(tx.toBytes(false, true))
+func TestFromBytesTruncated(t *testing.T) {
+	transfer, err := CreateTransaction(testRecipient(), 1, "truncated", "passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	vote, err := CreateVote("+", "034151a3ec46b5670a682b0a63394f863587d1bc97483b1b6c70eb58e7f0aed192", "this is a top secret passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	Equals(tx1.Type, tx.Type)
-	Equals(tx1.Timestamp, tx.Timestamp)
-	Equals(tx1.SenderPublicKey, tx.SenderPublicKey)
+	for _, tx := range []*Transaction{transfer, vote} {
+		txBytes, _ := tx.toBytes(false, false)
+		unsigned, _ := tx.toBytes(true, true)
+		signed, _ := tx.toBytes(false, true)
+		for n := 0; n < len(txBytes); n++ {
+			if n == len(unsigned) || n == len(signed) {
+				continue //valid transaction without signatures or without second signature
+			}
+			if _, err := FromBytes(txBytes[:n]); err == nil {
+				t.Error("Truncated transaction accepted", tx.Type, n)
+			}
+		}
+		if _, err := FromBytes(append(txBytes, 0)); err != ErrTransactionFormat {
+			t.Error("Trailing bytes accepted", err)
+		}
+	}
+
+	txBytes, _ := transfer.toBytes(false, false)
+	txBytes[0] = 99
+	if _, err := FromBytes(txBytes); err != ErrTransactionType {
+		t.Error("Unknown type accepted", err)
+	}
+}
+
+func testRecipient() string {
+	if EnvironmentParams.Network.Type == DEVNET {
+		return "DFTzLwEHKKn3VGce6vZSueEmoPWpEZswhB"
+	}
+	return "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25"
 }
 
 func Equals(s1, s2 interface{}) {
@@ -262,20 +400,29 @@ func TestPostToLocalNodeTransaction(t *testing.T) {
 		passphrase = "outer behind tray slice trash cave table divert wild buddy snap news"
 	}
 
-	tx := CreateTransaction(recepient,
+	tx, err := CreateTransaction(recepient,
 		1,
 		"1ARK-GOLang is saying whoop whooop",
 		passphrase, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	tx1 := CreateTransaction(recepient,
+	tx1, err := CreateTransaction(recepient,
 		2,
 		"2ARK-GOLang is saying whoop whooop",
 		passphrase, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
-	tx2 := CreateTransaction(recepient,
+	tx2, err := CreateTransaction(recepient,
 		3,
 		"3ARK-GOLang is saying whoop whooop",
 		passphrase, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
 	var payload TransactionPayload
 	payload.Transactions = append(payload.Transactions, tx)
@@ -309,20 +456,29 @@ func TestPostToLocalLoopNodeTransaction(t *testing.T) {
 	t0 := time.Now()
 
 	for i := 0; i < 1000; i++ {
-		tx := CreateTransaction(recepient,
+		tx, err := CreateTransaction(recepient,
 			1,
 			"1ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
-		tx1 := CreateTransaction(recepient,
+		tx1, err := CreateTransaction(recepient,
 			2,
 			"2ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
-		tx2 := CreateTransaction(recepient,
+		tx2, err := CreateTransaction(recepient,
 			3,
 			"3ARK-GOLang is saying whoop whooop",
 			passphrase, "")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
 		var payload TransactionPayload
 		payload.Transactions = append(payload.Transactions, tx)
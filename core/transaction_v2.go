@@ -0,0 +1,318 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin/base58"
+)
+
+//Transaction serialization versions
+const (
+	TransactionV1 = 1 //legacy format
+	TransactionV2 = 2 //AIP-11 format with network byte, type group and nonce
+)
+
+//CoreTypeGroup is AIP-11 type group of the core transaction types
+const CoreTypeGroup = 1
+
+//MaxVendorFieldLengthV2 is the maximum vendor field length of AIP-11 transactions
+const MaxVendorFieldLengthV2 = 255
+
+//txV2Header is the first byte of AIP-11 serialized transactions, legacy ones start with the type
+const txV2Header = 0xff
+
+//AIP-11 header length - header, version, network, type group, type, nonce, sender public key, fee
+const txV2HeaderLength = 1 + 1 + 1 + 4 + 2 + 8 + 33 + 8
+
+//hasVendorField reports whether AIP-11 transactions of type carry vendor field
+func hasVendorField(txType byte) bool {
+	return txType == SENDARK || txType == MULTIPAYMENT || txType == HTLCLOCK
+}
+
+//toBytesV2 returns AIP-11 serialized transaction. Timestamp is not part of it and vendor field is written
+//only for types supporting it. Multisignature lifetime is dropped, votes are a byte flag and public key.
+func (tx *Transaction) toBytesV2(skipSignature, skipSecondSignature bool) ([]byte, error) {
+	txBuf := new(bytes.Buffer)
+	binary.Write(txBuf, binary.LittleEndian, byte(txV2Header))
+	binary.Write(txBuf, binary.LittleEndian, tx.Version)
+	binary.Write(txBuf, binary.LittleEndian, tx.Network)
+	binary.Write(txBuf, binary.LittleEndian, tx.TypeGroup)
+	binary.Write(txBuf, binary.LittleEndian, uint16(tx.Type))
+	binary.Write(txBuf, binary.LittleEndian, tx.Nonce)
+	senderPublicKey, err := quickHexDecode("sender public key", tx.SenderPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	binary.Write(txBuf, binary.LittleEndian, senderPublicKey)
+	binary.Write(txBuf, binary.LittleEndian, uint64(tx.Fee))
+
+	if hasVendorField(tx.Type) && tx.VendorField != "" {
+		if len(tx.VendorField) > MaxVendorFieldLengthV2 {
+			return nil, fmt.Errorf("vendor field is longer than %d bytes", MaxVendorFieldLengthV2)
+		}
+		binary.Write(txBuf, binary.LittleEndian, byte(len(tx.VendorField)))
+		binary.Write(txBuf, binary.LittleEndian, []byte(tx.VendorField))
+	} else {
+		binary.Write(txBuf, binary.LittleEndian, byte(0))
+	}
+
+	switch tx.Type {
+	case SENDARK:
+		res, err := base58.Decode(tx.RecipientID)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %s", tx.RecipientID, err.Error())
+		}
+		binary.Write(txBuf, binary.LittleEndian, uint64(tx.Amount))
+		binary.Write(txBuf, binary.LittleEndian, tx.Expiration)
+		binary.Write(txBuf, binary.LittleEndian, res)
+	case SECONDSIGNATURE:
+		secondPublicKey, err := quickHexDecode("second signature public key", tx.Asset["signature"])
+		if err != nil {
+			return nil, err
+		}
+		binary.Write(txBuf, binary.LittleEndian, secondPublicKey)
+	case CREATEDELEGATE:
+		username := tx.Asset["username"]
+		binary.Write(txBuf, binary.LittleEndian, byte(len(username)))
+		binary.Write(txBuf, binary.LittleEndian, []byte(username))
+	case VOTE:
+		votes := tx.Asset["votes"]
+		if len(votes)%txVoteLength != 0 {
+			return nil, fmt.Errorf("votes %s: %s", votes, ErrTransactionFormat.Error())
+		}
+		binary.Write(txBuf, binary.LittleEndian, byte(len(votes)/txVoteLength))
+		for ; len(votes) > 0; votes = votes[txVoteLength:] {
+			key, err := hex.DecodeString(votes[1:txVoteLength])
+			if err != nil {
+				return nil, fmt.Errorf("vote %s: %s", votes[:txVoteLength], err.Error())
+			}
+			binary.Write(txBuf, binary.LittleEndian, votes[0] == '+')
+			binary.Write(txBuf, binary.LittleEndian, key)
+		}
+	case MULTISIGNATURE:
+		min, err := strconv.Atoi(tx.Asset["min"])
+		if err != nil {
+			return nil, fmt.Errorf("multisignature min: %s", err.Error())
+		}
+		keysgroup := strings.Split(tx.Asset["keysgroup"], ",")
+		binary.Write(txBuf, binary.LittleEndian, byte(min))
+		binary.Write(txBuf, binary.LittleEndian, byte(len(keysgroup)))
+		for _, member := range keysgroup {
+			key, err := hex.DecodeString(strings.TrimPrefix(member, "+"))
+			if err != nil {
+				return nil, fmt.Errorf("keysgroup member %s: %s", member, err.Error())
+			}
+			binary.Write(txBuf, binary.LittleEndian, key)
+		}
+	case MULTIPAYMENT:
+		binary.Write(txBuf, binary.LittleEndian, uint16(len(tx.Payments)))
+		for _, payment := range tx.Payments {
+			res, err := base58.Decode(payment.RecipientID)
+			if err != nil {
+				return nil, fmt.Errorf("recipient %s: %s", payment.RecipientID, err.Error())
+			}
+			binary.Write(txBuf, binary.LittleEndian, uint64(payment.Amount))
+			binary.Write(txBuf, binary.LittleEndian, res)
+		}
+	case HTLCLOCK:
+		secretHash, expiration, err := tx.HTLCLockAsset()
+		if err != nil {
+			return nil, err
+		}
+		res, err := base58.Decode(tx.RecipientID)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %s", tx.RecipientID, err.Error())
+		}
+		binary.Write(txBuf, binary.LittleEndian, uint64(tx.Amount))
+		binary.Write(txBuf, binary.LittleEndian, secretHash)
+		binary.Write(txBuf, binary.LittleEndian, expiration.Type)
+		binary.Write(txBuf, binary.LittleEndian, expiration.Value)
+		binary.Write(txBuf, binary.LittleEndian, res)
+	case HTLCCLAIM, HTLCREFUND:
+		lockID, err := hex.DecodeString(tx.Asset["lockTransactionId"])
+		if err != nil || len(lockID) != 32 {
+			return nil, ErrHTLCLockID
+		}
+		binary.Write(txBuf, binary.LittleEndian, lockID)
+		if tx.Type == HTLCCLAIM {
+			secret, err := hex.DecodeString(tx.Asset["unlockSecret"])
+			if err != nil || len(secret) != HTLCSecretLength {
+				return nil, ErrHTLCSecret
+			}
+			binary.Write(txBuf, binary.LittleEndian, secret)
+		}
+	default:
+		return nil, ErrTransactionType
+	}
+
+	if err := tx.writeSignatures(txBuf, skipSignature, skipSecondSignature); err != nil {
+		return nil, err
+	}
+	return txBuf.Bytes(), nil
+}
+
+//fromBytesV2 deserializes AIP-11 transaction, see FromBytes
+func fromBytesV2(txbytes []byte) (*Transaction, error) {
+	if len(txbytes) < txV2HeaderLength+1 {
+		return nil, ErrTransactionTruncated
+	}
+	if txbytes[1] != TransactionV2 {
+		return nil, ErrTransactionFormat
+	}
+	txType := binary.LittleEndian.Uint16(txbytes[7:9])
+	if txType > 0xff {
+		return nil, ErrTransactionType
+	}
+	tx := &Transaction{
+		Version:         txbytes[1],
+		Network:         txbytes[2],
+		TypeGroup:       binary.LittleEndian.Uint32(txbytes[3:7]),
+		Type:            byte(txType),
+		Nonce:           binary.LittleEndian.Uint64(txbytes[9:17]),
+		SenderPublicKey: hex.EncodeToString(txbytes[17:50]),
+		Fee:             int64(binary.LittleEndian.Uint64(txbytes[50:58])),
+	}
+	if tx.TypeGroup != CoreTypeGroup {
+		return nil, ErrTransactionType
+	}
+
+	vendorLength := int(txbytes[txV2HeaderLength])
+	rest := txbytes[txV2HeaderLength+1:]
+	if len(rest) < vendorLength {
+		return nil, ErrTransactionTruncated
+	}
+	if vendorLength > 0 && !hasVendorField(tx.Type) {
+		return nil, ErrTransactionFormat
+	}
+	tx.VendorField, rest = string(rest[:vendorLength]), rest[vendorLength:]
+
+	var err error
+	switch tx.Type {
+	case SENDARK:
+		if len(rest) < 8+4+21 {
+			return nil, ErrTransactionTruncated
+		}
+		tx.Amount = int64(binary.LittleEndian.Uint64(rest))
+		tx.Expiration = binary.LittleEndian.Uint32(rest[8:])
+		tx.RecipientID = base58.Encode(rest[12:33])
+		rest = rest[33:]
+	case SECONDSIGNATURE:
+		if len(rest) < 33 {
+			return nil, ErrTransactionTruncated
+		}
+		tx.Asset = map[string]string{"signature": hex.EncodeToString(rest[:33])}
+		rest = rest[33:]
+	case CREATEDELEGATE:
+		if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+			return nil, ErrTransactionTruncated
+		}
+		username := string(rest[1 : 1+rest[0]])
+		if !isDelegateUsername(username) {
+			return nil, ErrTransactionFormat
+		}
+		tx.Asset = map[string]string{"username": username}
+		rest = rest[1+rest[0]:]
+	case VOTE:
+		if len(rest) < 1 || len(rest) < 1+34*int(rest[0]) {
+			return nil, ErrTransactionTruncated
+		}
+		if rest[0] == 0 {
+			return nil, ErrTransactionFormat
+		}
+		var votes string
+		count := int(rest[0])
+		rest = rest[1:]
+		for i := 0; i < count; i++ {
+			switch rest[0] {
+			case 0:
+				votes += "-"
+			case 1:
+				votes += "+"
+			default:
+				return nil, ErrTransactionFormat
+			}
+			votes += hex.EncodeToString(rest[1:34])
+			rest = rest[34:]
+		}
+		tx.Asset = map[string]string{"votes": votes}
+	case MULTISIGNATURE:
+		if len(rest) < 2 || len(rest) < 2+33*int(rest[1]) {
+			return nil, ErrTransactionTruncated
+		}
+		if rest[1] == 0 {
+			return nil, ErrTransactionFormat
+		}
+		keysgroup := make([]string, rest[1])
+		for i := range keysgroup {
+			keysgroup[i] = "+" + hex.EncodeToString(rest[2+33*i:2+33*(i+1)])
+		}
+		tx.Asset = map[string]string{
+			"min":       strconv.Itoa(int(rest[0])),
+			"keysgroup": strings.Join(keysgroup, ","),
+		}
+		rest = rest[2+33*len(keysgroup):]
+	case MULTIPAYMENT:
+		if tx.Payments, rest, err = splitPayments(rest); err != nil {
+			return nil, err
+		}
+	case HTLCLOCK:
+		if len(rest) < 8+32+1+4+21 {
+			return nil, ErrTransactionTruncated
+		}
+		tx.Amount = int64(binary.LittleEndian.Uint64(rest))
+		tx.Asset = map[string]string{
+			"secretHash":      hex.EncodeToString(rest[8:40]),
+			"expirationType":  strconv.Itoa(int(rest[40])),
+			"expirationValue": strconv.FormatUint(uint64(binary.LittleEndian.Uint32(rest[41:45])), 10),
+		}
+		tx.RecipientID = base58.Encode(rest[45:66])
+		rest = rest[66:]
+	case HTLCCLAIM, HTLCREFUND:
+		length := 32
+		if tx.Type == HTLCCLAIM {
+			length += HTLCSecretLength
+		}
+		if len(rest) < length {
+			return nil, ErrTransactionTruncated
+		}
+		tx.Asset = map[string]string{"lockTransactionId": hex.EncodeToString(rest[:32])}
+		if tx.Type == HTLCCLAIM {
+			tx.Asset["unlockSecret"] = hex.EncodeToString(rest[32:length])
+		}
+		rest = rest[length:]
+	default:
+		return nil, ErrTransactionType
+	}
+
+	if err = tx.readSignatures(rest); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+//splitPayments reads multipayment count and payments (amount and recipient)
+func splitPayments(b []byte) ([]Payment, []byte, error) {
+	if len(b) < 2 {
+		return nil, nil, ErrTransactionTruncated
+	}
+	count := int(binary.LittleEndian.Uint16(b))
+	if count == 0 {
+		return nil, nil, ErrTransactionFormat
+	}
+	b = b[2:]
+	if len(b) < count*(8+21) {
+		return nil, nil, ErrTransactionTruncated
+	}
+	payments := make([]Payment, count)
+	for i := range payments {
+		payments[i].Amount = int64(binary.LittleEndian.Uint64(b))
+		payments[i].RecipientID = base58.Encode(b[8:29])
+		b = b[29:]
+	}
+	return payments, b, nil
+}
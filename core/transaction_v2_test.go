@@ -0,0 +1,188 @@
+package core
+
+import (
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dghubble/sling"
+)
+
+//useV2Network switches transaction creation to AIP-11 with nonces served by test peer, returns restore func
+func useV2Network(t *testing.T, nonce string) func() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/wallets/") {
+			t.Error("Unexpected request", r.URL.Path)
+		}
+		if nonce == "" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode":404,"error":"Not Found","message":"Wallet not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"address":"` + strings.TrimPrefix(r.URL.Path, "/api/wallets/") + `","nonce":"` + nonce + `"}}`))
+	}))
+
+	version, nonces := EnvironmentParams.Network.TransactionVersion, Nonces
+	EnvironmentParams.Network.TransactionVersion = TransactionV2
+	Nonces = NewNonceTracker(&ArkClient{sling: sling.New().Base(server.URL + "/")})
+	return func() {
+		EnvironmentParams.Network.TransactionVersion, Nonces = version, nonces
+		server.Close()
+	}
+}
+
+func TestTransactionV2(t *testing.T) {
+	defer useV2Network(t, "5")()
+
+	tx, err := CreateTransaction(testRecipient(), 100000000, "v2 transfer", "this is a top secret passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), tx.ToJSON())
+	if tx.Version != TransactionV2 || tx.Network != EnvironmentParams.Network.AddressVersion || tx.TypeGroup != CoreTypeGroup || tx.Nonce != 6 {
+		t.Error("Wrong v2 fields", tx.Version, tx.Network, tx.TypeGroup, tx.Nonce)
+	}
+	if !strings.Contains(tx.ToJSON(), `"nonce":"6"`) {
+		t.Error("Nonce is not JSON string", tx.ToJSON())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if err = tx.SecondVerify(); err != nil {
+		t.Error(err.Error())
+	}
+
+	unsignedBytes, _ := tx.toBytes(true, true)
+	if unsignedBytes[0] != txV2Header || len(unsignedBytes) != txV2HeaderLength+1+len(tx.VendorField)+8+4+21 {
+		t.Error("Wrong v2 serialization", hex.EncodeToString(unsignedBytes))
+	}
+
+	txBytes, _ := tx.toBytes(false, false)
+	parsed, err := FromBytes(txBytes)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed.ID != tx.ID || parsed.Nonce != 6 || parsed.RecipientID != tx.RecipientID || parsed.Amount != tx.Amount || parsed.VendorField != tx.VendorField {
+		t.Error("Wrong deserialized transaction", parsed.ToJSON())
+	}
+
+	//nonce is a part of signed bytes
+	tx.Nonce++
+	if err = tx.Verify(); err == nil {
+		t.Error("Changed nonce verified")
+	}
+}
+
+func TestTransactionV2Types(t *testing.T) {
+	defer useV2Network(t, "")()
+
+	delegateKey := hex.EncodeToString(NewPassphraseSigner("delegate").PublicKey())
+	vote, err := CreateVote("+", delegateKey, "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	delegate, err := CreateDelegate("arkgo_v2", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := CreateSecondSignature("this is a top secret passphrase", "second passphrase")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	multisig, err := CreateMultiSignature(1, 0, []string{delegateKey}, "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	multipayment, err := CreateMultiPayment(testPayments(3), "v2 payout", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for i, tx := range []*Transaction{vote, delegate, second, multisig, multipayment} {
+		if tx.Nonce != uint64(i+1) {
+			t.Error("Wrong nonce", tx.Type, tx.Nonce)
+		}
+		if tx.Type != MULTIPAYMENT && tx.VendorField != "" {
+			t.Error("Vendor field kept for type", tx.Type)
+		}
+		if err = tx.Verify(); err != nil {
+			t.Error(tx.Type, err.Error())
+		}
+		txBytes, _ := tx.toBytes(false, false)
+		parsed, err := FromBytes(txBytes)
+		if err != nil {
+			t.Error(tx.Type, err.Error())
+			continue
+		}
+		if parsed.ID != tx.ID {
+			t.Error("Wrong deserialized transaction", tx.Type, parsed.ToJSON())
+		}
+	}
+	if vote.Asset["votes"] != "+"+delegateKey {
+		t.Error("Wrong vote", vote.Asset["votes"])
+	}
+	if _, _, lifetime, err := multisig.MultiSignatureAsset(); err != nil || lifetime != 0 {
+		t.Error("Wrong multisignature asset", lifetime, err)
+	}
+
+	//nonce is fetched again after reset
+	Nonces.Reset(vote.SenderPublicKey)
+	if nonce, _ := Nonces.Next(vote.SenderPublicKey); nonce != 1 {
+		t.Error("Nonce not fetched after reset", nonce)
+	}
+}
+
+func TestFromBytesV2Errors(t *testing.T) {
+	defer useV2Network(t, "1")()
+
+	tx, err := CreateTransaction(testRecipient(), 1, "", "this is a top secret passphrase", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	txBytes, _ := tx.toBytes(true, true)
+	if _, err = FromBytes(txBytes[:len(txBytes)-1]); err != ErrTransactionTruncated {
+		t.Error("Truncated transaction accepted", err)
+	}
+	txBytes[3] = 2 //type group
+	if _, err = FromBytes(txBytes); err != ErrTransactionType {
+		t.Error("Unknown type group accepted", err)
+	}
+}
+
+func TestTransactionVersion(t *testing.T) {
+	for version, expected := range map[string]byte{"1.0.1": TransactionV1, "1.1.0": TransactionV1, "2.6.10": TransactionV2, "3.0.0": TransactionV2, "": TransactionV1} {
+		if transactionVersion(version) != expected {
+			t.Error("Wrong transaction version", version, transactionVersion(version))
+		}
+	}
+}
+
+func TestMultiSignatureTemplateV2(t *testing.T) {
+	member := hex.EncodeToString(NewPassphraseSigner("member").PublicKey())
+	template := Transaction{Type: MULTISIGNATURE, Asset: map[string]string{"min": "1", "keysgroup": "+" + member}}
+	signer := NewPassphraseSigner("this is a top secret passphrase")
+
+	//legacy registrations need lifetime
+	if result := SignBulk([]Transaction{template}, signer, nil, 1, nil)[0]; result.Err != ErrMultiSignatureLifetime {
+		t.Error("Legacy registration without lifetime signed", result.Err)
+	}
+
+	defer useV2Network(t, "5")()
+	tx, err := NewTransactionBuilder(MULTISIGNATURE).Asset("min", "1").Asset("keysgroup", "+"+member).Signer(signer, nil).Build()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = tx.Verify(); err != nil {
+		t.Error(err.Error())
+	}
+	if result := SignBulk([]Transaction{template}, signer, nil, 1, nil)[0]; result.Err != nil {
+		t.Error("Registration template rejected", result.Err)
+	}
+	if _, err = CreateMultiSignatureWithSigner(1, 0, []string{member}, signer, nil); err != nil {
+		t.Error("Registration without lifetime rejected", err)
+	}
+}
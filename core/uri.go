@@ -0,0 +1,140 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kristjank/ark-go/arkcoin"
+)
+
+//PaymentURIScheme is the scheme of Ark payment URIs - ark:ADDRESS?amount=1.5&vendorField=...&label=...
+const PaymentURIScheme = "ark"
+
+var (
+	//ErrPaymentURIScheme is returned when URI does not start with ark:
+	ErrPaymentURIScheme = errors.New("payment uri must start with " + PaymentURIScheme + ":")
+	//ErrPaymentURIAmount is returned for negative, malformed or too precise (over 8 decimals) amounts
+	ErrPaymentURIAmount = errors.New("payment uri amount is invalid")
+	//ErrPaymentURIParam is returned for unknown required (req-) parameters
+	ErrPaymentURIParam = errors.New("payment uri has unsupported required parameter")
+	//ErrVendorFieldTooLong is returned when vendor field is longer than arkcoin.MaxVendorFieldLength bytes
+	ErrVendorFieldTooLong = fmt.Errorf("vendor field is longer than %d bytes", arkcoin.MaxVendorFieldLength)
+)
+
+//ParsePaymentURI parses ark: payment URI into unsigned transfer Transaction template and label.
+//Recipient is validated for the active network, amount is in ARK (for example 1.5) and is optional.
+func ParsePaymentURI(uri string) (*Transaction, string, error) {
+	prefix := PaymentURIScheme + ":"
+	if len(uri) < len(prefix) || !strings.EqualFold(uri[:len(prefix)], prefix) {
+		return nil, "", ErrPaymentURIScheme
+	}
+	rest := strings.TrimPrefix(uri[len(prefix):], "//")
+
+	address, rawQuery := rest, ""
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		address, rawQuery = rest[:i], rest[i+1:]
+	}
+	if err := arkcoin.ValidateAddress(address, arkcoin.ActiveCoinConfig); err != nil {
+		return nil, "", err
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx := &Transaction{
+		Type:        SENDARK,
+		RecipientID: address,
+		Fee:         EnvironmentParams.Fees.Send,
+		VendorField: query.Get("vendorField"),
+	}
+	if amount := query.Get("amount"); amount != "" {
+		if tx.Amount, err = parseArkAmount(amount); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(tx.VendorField) > arkcoin.MaxVendorFieldLength {
+		return nil, "", ErrVendorFieldTooLong
+	}
+	for key := range query {
+		if strings.HasPrefix(key, "req-") {
+			return nil, "", ErrPaymentURIParam
+		}
+	}
+	return tx, query.Get("label"), nil
+}
+
+//PaymentURI returns ark: payment URI for recipient, amount and vendor field of transaction template.
+//Zero amount and empty vendor field or label are left out.
+func PaymentURI(tx *Transaction, label string) (string, error) {
+	if err := arkcoin.ValidateAddress(tx.RecipientID, arkcoin.ActiveCoinConfig); err != nil {
+		return "", err
+	}
+	if tx.Amount < 0 {
+		return "", ErrPaymentURIAmount
+	}
+	if len(tx.VendorField) > arkcoin.MaxVendorFieldLength {
+		return "", ErrVendorFieldTooLong
+	}
+
+	var params []string
+	if tx.Amount > 0 {
+		params = append(params, "amount="+formatArkAmount(tx.Amount))
+	}
+	if tx.VendorField != "" {
+		params = append(params, "vendorField="+uriEscape(tx.VendorField))
+	}
+	if label != "" {
+		params = append(params, "label="+uriEscape(label))
+	}
+
+	uri := PaymentURIScheme + ":" + tx.RecipientID
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri, nil
+}
+
+//uriEscape escapes query value, with spaces as %20 for wallets not decoding + as space
+func uriEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+//parseArkAmount converts decimal ARK amount to satoshis without floating point rounding
+func parseArkAmount(amount string) (int64, error) {
+	whole, fraction := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		whole, fraction = amount[:i], amount[i+1:]
+	}
+	if whole == "" && fraction == "" || len(fraction) > 8 || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, ErrPaymentURIAmount
+	}
+
+	var result int64
+	if whole != "" {
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || w > (1<<63-1)/SATOSHI {
+			return 0, ErrPaymentURIAmount
+		}
+		result = w * SATOSHI
+	}
+	if fraction != "" {
+		f, err := strconv.ParseInt(fraction+strings.Repeat("0", 8-len(fraction)), 10, 64)
+		if err != nil || result > 1<<63-1-f {
+			return 0, ErrPaymentURIAmount
+		}
+		result += f
+	}
+	return result, nil
+}
+
+//formatArkAmount formats satoshis as decimal ARK amount without trailing zeros
+func formatArkAmount(satoshi int64) string {
+	if satoshi%SATOSHI == 0 {
+		return strconv.FormatInt(satoshi/SATOSHI, 10)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%08d", satoshi/SATOSHI, satoshi%SATOSHI), "0")
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestPaymentURI(t *testing.T) {
+	tx := &Transaction{RecipientID: testRecipient(), Amount: 150000000, VendorField: "pool donation & thanks"}
+	uri, err := PaymentURI(tx, "ark-go pool")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	log.Println(t.Name(), uri)
+	if uri != "ark:"+testRecipient()+"?amount=1.5&vendorField=pool%20donation%20%26%20thanks&label=ark-go%20pool" {
+		t.Error("Wrong uri", uri)
+	}
+
+	parsed, label, err := ParsePaymentURI(uri)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed.RecipientID != tx.RecipientID || parsed.Amount != tx.Amount || parsed.VendorField != tx.VendorField || label != "ark-go pool" {
+		t.Error("Parsed uri differs", parsed.RecipientID, parsed.Amount, parsed.VendorField, label)
+	}
+	if parsed.Type != SENDARK || parsed.Fee != EnvironmentParams.Fees.Send {
+		t.Error("Wrong transaction template", parsed.Type, parsed.Fee)
+	}
+
+	if uri, _ = PaymentURI(&Transaction{RecipientID: testRecipient()}, ""); uri != "ark:"+testRecipient() {
+		t.Error("Wrong uri without parameters", uri)
+	}
+}
+
+func TestParsePaymentURIAmounts(t *testing.T) {
+	amounts := map[string]int64{
+		"1":          100000000,
+		"1.5":        150000000,
+		"0.00000001": 1,
+		".25":        25000000,
+		"12.":        1200000000,
+	}
+	for amount, satoshi := range amounts {
+		tx, _, err := ParsePaymentURI("ark:" + testRecipient() + "?amount=" + amount)
+		if err != nil {
+			t.Error(amount, err.Error())
+			continue
+		}
+		if tx.Amount != satoshi {
+			t.Error("Wrong amount", amount, tx.Amount)
+		}
+	}
+	for satoshi, amount := range map[int64]string{100000000: "1", 150000000: "1.5", 1: "0.00000001", 0: "0"} {
+		if formatArkAmount(satoshi) != amount {
+			t.Error("Wrong formatted amount", formatArkAmount(satoshi))
+		}
+	}
+
+	for _, amount := range []string{"-1", "+1", "1.123456789", "abc", ".", "92233720368.54775808"} {
+		if _, _, err := ParsePaymentURI("ark:" + testRecipient() + "?amount=" + amount); err != ErrPaymentURIAmount {
+			t.Error("Amount accepted", amount, err)
+		}
+	}
+}
+
+func TestParsePaymentURIErrors(t *testing.T) {
+	if _, _, err := ParsePaymentURI("bitcoin:" + testRecipient()); err != ErrPaymentURIScheme {
+		t.Error("Wrong scheme accepted", err)
+	}
+	if _, _, err := ParsePaymentURI("ark:" + testRecipient() + "?vendorField=" + strings.Repeat("x", 65)); err != ErrVendorFieldTooLong {
+		t.Error("Long vendor field accepted", err)
+	}
+	if _, _, err := ParsePaymentURI("ark:" + testRecipient() + "?req-expires=1"); err != ErrPaymentURIParam {
+		t.Error("Unknown required parameter accepted", err)
+	}
+	otherNetwork := "DFTzLwEHKKn3VGce6vZSueEmoPWpEZswhB"
+	if EnvironmentParams.Network.Type == DEVNET {
+		otherNetwork = "AXoXnFi4z1Z6aFvjEYkDVCtBGW2PaRiM25"
+	}
+	if _, _, err := ParsePaymentURI("ark:" + otherNetwork); err == nil {
+		t.Error("Address of other network accepted")
+	}
+	if _, err := PaymentURI(&Transaction{RecipientID: otherNetwork}, ""); err == nil {
+		t.Error("Uri generated for address of other network")
+	}
+}